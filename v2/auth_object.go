@@ -275,7 +275,7 @@ func (obj *AuthObject) Write(connOption *network.ConnectionOption, mode LogonMod
 	index++
 
 	session.ResetBuffer()
-	session.PutBytes(3, 0x73, 0)
+	session.PutFunctionCode(3, 0x73)
 	if len(connOption.UserID) > 0 {
 		session.PutBytes(1)
 		session.PutInt(len(connOption.UserID), 4, true, true)
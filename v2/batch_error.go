@@ -0,0 +1,68 @@
+package go_ora
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// BatchOption is passed as the last argument of array execute
+//
+//	var counts []int64
+//	_, err := db.Exec("INSERT INTO T1(ID, NAME) VALUES(:1, :2)", ids, names,
+//		go_ora.BatchOption{ContinueOnError: true, RowCounts: &counts})
+//	var batchErr *go_ora.BatchError
+//	if errors.As(err, &batchErr) {
+//		// quarantine batchErr.Errors[i].Iteration
+//	}
+//
+// ContinueOnError: execute all iterations and return failed ones in
+// BatchError instead of stopping at the first error
+//
+// RowCounts: receive number of rows affected by each iteration
+type BatchOption struct {
+	ContinueOnError bool
+	RowCounts       *[]int64
+}
+
+// IterationError is the error of one iteration of array execute.
+// Iteration is zero based index of the row in the bound slices
+type IterationError struct {
+	Iteration int
+	ErrCode   int
+	ErrMsg    string
+}
+
+// BatchError is returned from array execute in continue on error mode when
+// some iterations failed. other iterations are applied and RowsAffected
+// contain their total
+type BatchError struct {
+	Errors       []IterationError
+	RowsAffected int64
+	RowCounts    []int64
+}
+
+func (err *BatchError) Error() string {
+	if len(err.Errors) == 0 {
+		return "array execute failed"
+	}
+	msg := strings.TrimSpace(err.Errors[0].ErrMsg)
+	if len(err.Errors) == 1 {
+		return fmt.Sprintf("iteration %d failed: %s", err.Errors[0].Iteration, msg)
+	}
+	return fmt.Sprintf("%d iterations failed, first at iteration %d: %s", len(err.Errors),
+		err.Errors[0].Iteration, msg)
+}
+
+// takeBatchOption remove BatchOption from the arguments
+func takeBatchOption(args []driver.NamedValue) ([]driver.NamedValue, *BatchOption) {
+	for x := len(args) - 1; x >= 0; x-- {
+		switch val := args[x].Value.(type) {
+		case BatchOption:
+			return append(args[:x:x], args[x+1:]...), &val
+		case *BatchOption:
+			return append(args[:x:x], args[x+1:]...), val
+		}
+	}
+	return args, nil
+}
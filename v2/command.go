@@ -2,10 +2,16 @@ package go_ora
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
 	"database/sql/driver"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"reflect"
 	"time"
 
 	"github.com/sijms/go-ora/v2/converters"
@@ -16,6 +22,11 @@ import (
 	"strings"
 )
 
+// Compile time Sentinels for implemented Interfaces.
+var _ = driver.StmtExecContext((*Stmt)(nil))
+var _ = driver.StmtQueryContext((*Stmt)(nil))
+var _ = driver.NamedValueChecker((*Stmt)(nil))
+
 type StmtType int
 
 const (
@@ -55,6 +66,8 @@ type defaultStmt struct {
 	columns            []ParameterInfo
 	scnForSnapshot     []int
 	arrayBindCount     int
+	arrayRowCounts     []int64
+	implicitResults    []*RefCursor
 }
 
 func (stmt *defaultStmt) hasMoreRows() bool {
@@ -72,7 +85,7 @@ func (stmt *defaultStmt) hasBLOB() bool {
 
 func (stmt *defaultStmt) basicWrite(exeOp int, parse, define bool) error {
 	session := stmt.connection.session
-	session.PutBytes(3, 0x5E, 0)
+	session.PutFunctionCode(3, 0x5E)
 	session.PutUint(exeOp, 4, true, true)
 	session.PutUint(stmt.cursorID, 2, true, true)
 	if stmt.cursorID == 0 {
@@ -219,23 +232,43 @@ type Stmt struct {
 	parse        bool // means parse the command in the server this occur if the stmt is not cached
 	execute      bool
 	define       bool
+	batchOption  *BatchOption
+	// temporaryLobs hold LOBs created to bind large values
+	temporaryLobs []*LobLocator
+	// paramError hold error of NewParam and AddParam. it is returned by
+	// the following executions
+	paramError error
 
 	//noOfDefCols        int
 }
 
 type QueryResult struct {
-	lastInsertedID int64
-	rowsAffected   int64
+	rowID        string
+	rowsAffected int64
+	rowCounts    []int64
 }
 
+// LastInsertId is not supported. oracle has no auto increment id that
+// belongs to the session. use RowID or returning clause instead
 func (rs *QueryResult) LastInsertId() (int64, error) {
-	return rs.lastInsertedID, nil
+	return 0, errors.New("LastInsertId is not supported, use RowID or RETURNING INTO")
+}
+
+// RowID return the rowid of the last row inserted, updated or deleted by
+// the statement or empty string when no row is affected
+func (rs *QueryResult) RowID() string {
+	return rs.rowID
 }
 
 func (rs *QueryResult) RowsAffected() (int64, error) {
 	return rs.rowsAffected, nil
 }
 
+// RowCounts return number of rows affected by each iteration of array execute
+func (rs *QueryResult) RowCounts() []int64 {
+	return rs.rowCounts
+}
+
 func NewStmt(text string, conn *Connection) *Stmt {
 	ret := &Stmt{
 		//connection:         conn,
@@ -275,12 +308,19 @@ func NewStmt(text string, conn *Connection) *Stmt {
 		ret.stmtType = OTHERS
 	}
 
-	// returning clause
-	var err error
 	if ret.stmtType != PLSQL {
-		ret._hasReturnClause, err = regexp.MatchString(`\bRETURNING\b`, uCmdText)
-		if err != nil {
-			ret._hasReturnClause = false
+		ret._hasReturnClause = hasReturnClause(text)
+	}
+	// reuse server cursor of a closed statement with the same text
+	if conn.stmtCache != nil {
+		if cached := conn.stmtCache.get(text); cached != nil {
+			ret.cursorID = cached.cursorID
+			ret.columns = cached.columns
+			ret._hasLONG = cached.hasLONG
+			ret._hasBLOB = cached.hasBLOB
+			ret.queryID = cached.queryID
+			ret.parse = false
+			ret.reSendParDef = true
 		}
 	}
 	return ret
@@ -295,7 +335,7 @@ func (stmt *Stmt) write(session *network.Session) error {
 			count = stmt.arrayBindCount
 		}
 		if stmt.stmtType == SELECT {
-			session.PutBytes(3, 0x4E, 0)
+			session.PutFunctionCode(3, 0x4E)
 			count = stmt._noOfRowsToFetch
 			exeOf = 0x20
 			if stmt._hasReturnClause || stmt.stmtType == PLSQL || stmt.disableCompression {
@@ -303,7 +343,7 @@ func (stmt *Stmt) write(session *network.Session) error {
 			}
 
 		} else {
-			session.PutBytes(3, 4, 0)
+			session.PutFunctionCode(3, 4)
 		}
 		if stmt.connection.autoCommit {
 			execFlag = 1
@@ -397,20 +437,13 @@ func (stmt *Stmt) write(session *network.Session) error {
 	//}
 
 	if len(stmt.Pars) > 0 {
-		session.PutBytes(7)
-		for _, par := range stmt.Pars {
-			if par.DataType != RAW {
-				if par.DataType == REFCURSOR {
-					session.PutBytes(1, 0)
-				} else {
-					session.PutClr(par.BValue)
-				}
-			}
-		}
-		for _, par := range stmt.Pars {
-			if par.DataType == RAW {
-				session.PutClr(par.BValue)
+		if stmt.arrayBindCount > 0 {
+			// one row of values for each iteration
+			for x := 0; x < stmt.arrayBindCount; x++ {
+				stmt.writeRowData(session, x)
 			}
+		} else {
+			stmt.writeRowData(session, -1)
 		}
 		//for x := 0; x < stmt.arrayBindCount; x++ {
 		//
@@ -423,12 +456,58 @@ func (stmt *Stmt) write(session *network.Session) error {
 	return session.Write()
 }
 
+// writeRowData write parameter values. row is the iteration of array
+// binding or -1 for scalar parameters
+func (stmt *Stmt) writeRowData(session *network.Session, row int) {
+	value := func(par *ParameterInfo) []byte {
+		if row >= 0 {
+			return par.arrayBValues[row]
+		}
+		return par.BValue
+	}
+	session.PutBytes(7)
+	for x := range stmt.Pars {
+		par := &stmt.Pars[x]
+		if par.DataType != RAW {
+			if par.DataType == REFCURSOR {
+				session.PutBytes(1, 0)
+			} else if par.DataType == XMLType && len(value(par)) > 0 {
+				// object with type oid and pickled image
+				session.PutUint(len(par.ToID), 4, true, true)
+				session.PutClr(par.ToID)
+				session.PutBytes(0, 0, 0)
+				session.PutUint(len(value(par)), 4, true, true)
+				session.PutUint(1, 4, true, true)
+				session.PutClr(value(par))
+			} else if (par.DataType == OCIBlobLocator || par.DataType == OCIClobLocator) && len(value(par)) > 0 {
+				session.PutUint(len(value(par)), 4, true, true)
+				session.PutClr(value(par))
+			} else if (par.DataType == JSON || par.DataType == VECTOR) && len(value(par)) > 0 {
+				// OSON or VECTOR image follow value based locator
+				locator := valueLocator(len(value(par)))
+				session.PutUint(len(locator), 4, true, true)
+				session.PutClr(locator)
+				session.PutClr(value(par))
+			} else {
+				session.PutClr(value(par))
+			}
+		}
+	}
+	for x := range stmt.Pars {
+		par := &stmt.Pars[x]
+		if par.DataType == RAW {
+			session.PutClr(value(par))
+		}
+	}
+}
+
 func (stmt *Stmt) getExeOption() int {
 	op := 0
 	if stmt.stmtType == PLSQL || stmt._hasReturnClause {
 		op |= 0x40000
 	}
-	if stmt.arrayBindCount > 1 {
+	if stmt.arrayBindCount > 0 && stmt.batchOption != nil && stmt.batchOption.ContinueOnError {
+		// batch errors
 		op |= 0x80000
 	}
 	if stmt.connection.autoCommit && (stmt.stmtType == DML || stmt.stmtType == PLSQL) {
@@ -463,9 +542,35 @@ func (stmt *Stmt) getExeOption() int {
 	*/
 }
 
+// isBatchError report ORA-24381: error(s) in array DML. the errors of the
+// iterations are returned as BatchError after the execute complete
+func (stmt *defaultStmt) isBatchError() bool {
+	summary := stmt.connection.session.Summary
+	return summary.RetCode == 24381 && len(summary.BindErrors) > 0
+}
+
 func (stmt *defaultStmt) fetch(dataSet *DataSet) error {
+	if dataSet.ctx == nil {
+		return stmt.fetchRows(dataSet)
+	}
+	if err := dataSet.ctx.Err(); err != nil {
+		return err
+	}
+	call := stmt.connection.session.StartContext(dataSet.ctx)
+	err := stmt.fetchRows(dataSet)
+	if endErr := stmt.connection.session.EndContext(call); endErr != nil && err == nil {
+		err = endErr
+	}
+	if err != nil && dataSet.ctx.Err() != nil {
+		return dataSet.ctx.Err()
+	}
+	return err
+}
+
+// fetchRows send fetch request of the next rows and read them
+func (stmt *defaultStmt) fetchRows(dataSet *DataSet) error {
 	stmt.connection.session.ResetBuffer()
-	stmt.connection.session.PutBytes(3, 5, 0)
+	stmt.connection.session.PutFunctionCode(3, 5)
 	stmt.connection.session.PutInt(stmt.cursorID, 2, true, true)
 	stmt.connection.session.PutInt(stmt._noOfRowsToFetch, 2, true, true)
 	err := stmt.connection.session.Write()
@@ -499,7 +604,7 @@ func (stmt *defaultStmt) read(dataSet *DataSet) error {
 				if stmt.connection.session.Summary.RetCode == 1403 {
 					stmt._hasMoreRows = false
 					stmt.connection.session.Summary = nil
-				} else {
+				} else if !stmt.isBatchError() {
 					return stmt.connection.session.GetError()
 				}
 
@@ -527,13 +632,13 @@ func (stmt *defaultStmt) read(dataSet *DataSet) error {
 						if err != nil {
 							return err
 						}
-						if num > 1 {
+						if num > 1 && !stmt.Pars[x].returnArray {
 							return errors.New("more than one row affected with return clause")
 						}
-						if num == 0 {
-							stmt.Pars[x].BValue = nil
-							stmt.Pars[x].Value = nil
-						} else {
+						values := make([]driver.Value, 0, num)
+						stmt.Pars[x].BValue = nil
+						stmt.Pars[x].Value = nil
+						for row := 0; row < num; row++ {
 							stmt.Pars[x].BValue, err = session.GetClr()
 							if err != nil {
 								return err
@@ -546,6 +651,10 @@ func (stmt *defaultStmt) read(dataSet *DataSet) error {
 							if err != nil {
 								return err
 							}
+							values = append(values, stmt.Pars[x].Value)
+						}
+						if stmt.Pars[x].returnArray {
+							stmt.Pars[x].Value = values
 						}
 					}
 				}
@@ -595,6 +704,10 @@ func (stmt *defaultStmt) read(dataSet *DataSet) error {
 							if err != nil {
 								return err
 							}
+							if cursor, ok := dataSet.Cols[x].Value.(*RefCursor); ok {
+								cursor.row = len(dataSet.Rows)
+								dataSet.nested = append(dataSet.nested, cursor)
+							}
 							if dataSet.Cols[x].DataType == LONG || dataSet.Cols[x].DataType == LongRaw {
 								_, err = session.GetInt(4, true, true)
 								if err != nil {
@@ -680,13 +793,13 @@ func (stmt *defaultStmt) read(dataSet *DataSet) error {
 				if err != nil {
 					return err
 				}
-				//for (int index = 0; index < length3; ++index)
-				//	rowsAffectedByArrayBind[index] = this.m_marshallingEngine.UnmarshalSB8();
+				stmt.arrayRowCounts = make([]int64, length)
 				for i := 0; i < length; i++ {
-					_, err = session.GetInt(8, true, true)
+					count, err := session.GetInt(8, true, true)
 					if err != nil {
 						return err
 					}
+					stmt.arrayRowCounts[i] = int64(count)
 				}
 			}
 		case 11:
@@ -775,6 +888,33 @@ func (stmt *defaultStmt) read(dataSet *DataSet) error {
 				}
 			}
 			dataSet.setBitVector(bitVector)
+		case 27:
+			// implicit results returned by DBMS_SQL.RETURN_RESULT
+			count, err := session.GetInt(4, true, true)
+			if err != nil {
+				return err
+			}
+			stmt.implicitResults = make([]*RefCursor, 0, count)
+			for x := 0; x < count; x++ {
+				size, err := session.GetByte()
+				if err != nil {
+					return err
+				}
+				if size > 0 {
+					_, err = session.GetBytes(int(size))
+					if err != nil {
+						return err
+					}
+				}
+				cursor := &RefCursor{}
+				cursor.connection = stmt.connection
+				cursor.parent = stmt
+				err = cursor.describe()
+				if err != nil {
+					return err
+				}
+				stmt.implicitResults = append(stmt.implicitResults, cursor)
+			}
 		case 23:
 			opCode, err := session.GetByte()
 			if err != nil {
@@ -797,7 +937,7 @@ func (stmt *defaultStmt) requestCustomTypeInfo(typeName string) error {
 	session := stmt.connection.session
 	session.SaveState()
 	session.ResetBuffer()
-	session.PutBytes(0x3, 0x5c, 0)
+	session.PutFunctionCode(0x3, 0x5c)
 	session.PutInt(3, 4, true, true)
 	//session.PutInt(0x5C0003, 4, true, true)
 	//session.PutBytes(bytes.Repeat([]byte{0}, 79)...)
@@ -879,9 +1019,21 @@ func (stmt *defaultStmt) calculateParameterValue(param *ParameterInfo) error {
 		param.Value = nil
 		return nil
 	}
+	if param.DataType == REFCURSOR || param.DataType == ResultSet {
+		// cursor in select list
+		cursor := &RefCursor{}
+		cursor.connection = stmt.connection
+		cursor.parent = stmt
+		err = cursor.decode()
+		if err != nil {
+			return err
+		}
+		param.Value = cursor
+		return nil
+	}
 	if param.DataType == XMLType {
 		if param.TypeName == "XMLTYPE" {
-			return errors.New("unsupported data type: XMLTYPE")
+			return stmt.decodeXML(param)
 		}
 		if param.cusType == nil {
 			return fmt.Errorf("unregister custom type: %s. call RegisterType first", param.TypeName)
@@ -948,30 +1100,105 @@ func (stmt *defaultStmt) calculateParameterValue(param *ParameterInfo) error {
 			param.Value = stmt.connection.strConv.Decode(param.BValue)
 		}
 	case NUMBER:
-		param.Value = converters.DecodeNumber(param.BValue)
+		format := stmt.connection.conStr.NumberFormat
+		if exactNumberOutput(param.outDest) {
+			format = NumberAsDecimal
+		}
+		switch format {
+		case NumberAsDecimal:
+			param.Value, err = newDecimalFromBytes(param.BValue)
+		case NumberAsString:
+			param.Value, err = converters.NumberToString(param.BValue)
+		default:
+			param.Value = converters.DecodeNumber(param.BValue)
+		}
+		if err != nil {
+			return err
+		}
+	case TimeStampTZ, TimeStampTZ_DTY:
+		param.Value, err = converters.DecodeTimeStampTZ(param.BValue)
+		if err != nil {
+			return err
+		}
+	case TimeStampeLTZ, TimeStampLTZ_DTY:
+		// the server send the value in session time zone
+		dateVal, err := converters.DecodeDate(param.BValue)
+		if err != nil {
+			return err
+		}
+		param.Value = time.Date(dateVal.Year(), dateVal.Month(), dateVal.Day(), dateVal.Hour(),
+			dateVal.Minute(), dateVal.Second(), dateVal.Nanosecond(), stmt.connection.sessionLocation())
 	case TimeStamp:
 		fallthrough
 	case TimeStampDTY:
 		fallthrough
-	case TimeStampeLTZ:
-		fallthrough
-	case TimeStampLTZ_DTY:
-		fallthrough
-	case TimeStampTZ:
-		fallthrough
-	case TimeStampTZ_DTY:
-		fallthrough
 	case DATE:
 		dateVal, err := converters.DecodeDate(param.BValue)
 		if err != nil {
 			return err
 		}
 		param.Value = dateVal
+	case BOOLEAN:
+		param.Value = converters.DecodeBool(param.BValue)
+	case SB4:
+		if len(param.BValue) == 4 {
+			param.Value = int64(int32(binary.BigEndian.Uint32(param.BValue)))
+		} else {
+			param.Value = int64(converters.DecodeInt(param.BValue))
+		}
+	case IBFloat, BFloat:
+		param.Value, err = converters.DecodeBinaryFloat(param.BValue)
+		if err != nil {
+			return err
+		}
+	case IBDouble, BDouble:
+		param.Value, err = converters.DecodeBinaryDouble(param.BValue)
+		if err != nil {
+			return err
+		}
+	case IntervalDS, IntervalDS_DTY:
+		param.Value, err = converters.DecodeIntervalDS(param.BValue)
+		if err != nil {
+			return err
+		}
+	case IntervalYM, IntervalYM_DTY:
+		param.Value, err = converters.DecodeIntervalYM(param.BValue)
+		if err != nil {
+			return err
+		}
+	case JSON, VECTOR:
+		data, err := session.GetClr()
+		if err != nil {
+			return err
+		}
+		lob := &Lob{
+			sourceLocator: data,
+		}
+		session.SaveState()
+		lobData, err := lob.getData(stmt.connection)
+		if err != nil {
+			return err
+		}
+		session.LoadState()
+		if param.DataType == VECTOR {
+			param.Value, err = converters.DecodeVector(lobData)
+		} else {
+			param.Value, err = converters.DecodeOSON(lobData)
+		}
+		if err != nil {
+			return err
+		}
 	case OCIBlobLocator, OCIClobLocator:
 		data, err := session.GetClr()
 		if err != nil {
 			return err
 		}
+		if stmt.connection.conStr.LobFetch == LobFetchStream && param.Direction == 0 {
+			// only select list columns (without direction) are streamed.
+			// data is read when the locator is used
+			param.Value = newLobLocator(stmt.connection, data, param.DataType == OCIClobLocator, param.CharsetID)
+			return nil
+		}
 		lob := &Lob{
 			sourceLocator: data,
 		}
@@ -991,18 +1218,7 @@ func (stmt *defaultStmt) calculateParameterValue(param *ParameterInfo) error {
 			}
 			param.Value = lobData
 		} else {
-			tempCharset := stmt.connection.strConv.GetLangID()
-			if lob.variableWidthChar() {
-				if stmt.connection.dBVersion.Number < 10200 && lob.littleEndianClob() {
-					stmt.connection.strConv.SetLangID(2002)
-				} else {
-					stmt.connection.strConv.SetLangID(2000)
-				}
-			} else {
-				stmt.connection.strConv.SetLangID(param.CharsetID)
-			}
-			resultClobString := stmt.connection.strConv.Decode(lobData)
-			stmt.connection.strConv.SetLangID(tempCharset)
+			resultClobString := lob.decodeString(stmt.connection, lobData, param.CharsetID)
 			if dataSize != int64(len([]rune(resultClobString))) {
 				return errors.New("error reading clob data")
 			}
@@ -1013,35 +1229,162 @@ func (stmt *defaultStmt) calculateParameterValue(param *ParameterInfo) error {
 	}
 	return nil
 }
+
+// decodeXML read XMLTYPE object image. the document is stored in the
+// image or in CLOB
+func (stmt *defaultStmt) decodeXML(param *ParameterInfo) error {
+	session := stmt.connection.session
+	// type oid, oid and snapshot
+	for x := 0; x < 3; x++ {
+		_, err := session.GetDlc()
+		if err != nil {
+			return err
+		}
+	}
+	_, err := session.GetInt(2, true, true) // version
+	if err != nil {
+		return err
+	}
+	size, err := session.GetInt(4, true, true)
+	if err != nil {
+		return err
+	}
+	_, err = session.GetInt(2, true, true) // flags
+	if err != nil {
+		return err
+	}
+	if size == 0 {
+		param.Value = nil
+		return nil
+	}
+	image, err := session.GetClr()
+	if err != nil {
+		return err
+	}
+	flag, data, err := converters.DecodeXMLImage(image)
+	if err != nil {
+		return err
+	}
+	if flag == converters.XMLImageString {
+		param.Value = string(data)
+		return nil
+	}
+	lob := &Lob{
+		sourceLocator: data,
+	}
+	session.SaveState()
+	lobData, err := lob.getData(stmt.connection)
+	if err != nil {
+		return err
+	}
+	session.LoadState()
+	param.Value = lob.decodeString(stmt.connection, lobData, stmt.connection.tcpNego.ServerCharset)
+	return nil
+}
+
 func (stmt *defaultStmt) Close() error {
 	if stmt.cursorID != 0 {
-		session := stmt.connection.session
-		session.ResetBuffer()
-		session.PutBytes(17, 105, 0, 1, 1, 1)
-		session.PutInt(stmt.cursorID, 4, true, true)
-		return (&simpleObject{
-			connection:  stmt.connection,
-			operationID: 0x93,
-			data:        nil,
-			err:         nil,
-		}).write().read()
+		return closeCursors(stmt.connection, []int{stmt.cursorID})
 	}
 	return nil
 }
 
-func (stmt *Stmt) Exec(args []driver.Value) (driver.Result, error) {
-	stmt.connection.connOption.Tracer.Printf("Exec:\n%s", stmt.text)
-	for x := 0; x < len(args); x++ {
-		par := *stmt.NewParam("", args[x], 0, Input)
-		if x < len(stmt.Pars) {
-			if par.MaxLen > stmt.Pars[x].MaxLen {
-				stmt.reSendParDef = true
+// closeCursors close server cursors in one round trip
+func closeCursors(conn *Connection, cursorIDs []int) error {
+	session := conn.session
+	session.ResetBuffer()
+	session.PutFunctionCode(17, 105)
+	session.PutBytes(1)
+	session.PutInt(len(cursorIDs), 4, true, true)
+	for _, cursorID := range cursorIDs {
+		session.PutInt(cursorID, 4, true, true)
+	}
+	return (&simpleObject{
+		connection:  conn,
+		operationID: 0x93,
+		data:        nil,
+		err:         nil,
+	}).write().read()
+}
+
+var returnClauseRegexp = regexp.MustCompile(`\bRETURNING\b`)
+
+// hasReturnClause report if sql text has RETURNING INTO clause outside
+// literals and comments
+func hasReturnClause(text string) bool {
+	var code strings.Builder
+	for x := 0; x < len(text); x++ {
+		end := -1
+		switch {
+		case text[x] == '\'' || text[x] == '"':
+			if index := strings.IndexByte(text[x+1:], text[x]); index >= 0 {
+				end = x + 1 + index
 			}
-			stmt.Pars[x] = par
-		} else {
-			stmt.Pars = append(stmt.Pars, par)
+		case strings.HasPrefix(text[x:], "--"):
+			if index := strings.IndexByte(text[x:], '\n'); index >= 0 {
+				end = x + index
+			}
+		case strings.HasPrefix(text[x:], "/*"):
+			if index := strings.Index(text[x:], "*/"); index >= 0 {
+				end = x + index + 1
+			}
+		default:
+			code.WriteByte(text[x])
+			continue
+		}
+		if end < 0 {
+			break
 		}
-		stmt.connection.connOption.Tracer.Printf("    %d:\n%v", x, args[x])
+		// keep words apart
+		code.WriteByte(' ')
+		x = end
+	}
+	return returnClauseRegexp.MatchString(strings.ToUpper(code.String()))
+}
+
+// Close keep the server cursor in the connection statement cache when it
+// is enabled otherwise the cursor is closed
+func (stmt *Stmt) Close() error {
+	var err error
+	if len(stmt.temporaryLobs) > 0 && stmt.connection.session != nil {
+		// the cursor is closed even if the LOBs are not freed
+		err = stmt.freeTemporaryLobs()
+	}
+	cache := stmt.connection.stmtCache
+	if cache == nil || stmt.cursorID == 0 || stmt.connection.session == nil {
+		if closeErr := stmt.defaultStmt.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	evicted := cache.put(&cachedStmt{
+		connection: stmt.connection,
+		text:       stmt.text,
+		cursorID:   stmt.cursorID,
+		stmtType:   stmt.stmtType,
+		columns:    stmt.columns,
+		hasLONG:    stmt._hasLONG,
+		hasBLOB:    stmt._hasBLOB,
+		queryID:    stmt.queryID,
+	})
+	stmt.cursorID = 0
+	for _, item := range evicted {
+		if tempErr := item.close(); tempErr != nil && err == nil {
+			err = tempErr
+		}
+	}
+	return err
+}
+
+func (stmt *Stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return stmt.exec(toNamedValues(args))
+}
+
+func (stmt *Stmt) exec(args []driver.NamedValue) (driver.Result, error) {
+	stmt.connection.connOption.Tracer.Printf("Exec:\n%s", stmt.text)
+	err := stmt.bindArgs(args)
+	if err != nil {
+		return nil, err
 	}
 	session := stmt.connection.session
 	//if len(args) > 0 {
@@ -1051,27 +1394,377 @@ func (stmt *Stmt) Exec(args []driver.Value) (driver.Result, error) {
 	//	stmt.AddParam("", args[x], 0, Input)
 	//}
 	session.ResetBuffer()
-	err := stmt.write(session)
+	err = stmt.write(session)
 	if err != nil {
 		return nil, err
 	}
 	dataSet := new(DataSet)
+	stmt.implicitResults = nil
 	err = stmt.read(dataSet)
 	if err != nil {
 		return nil, err
 	}
+	// implicit results are returned only from Query
+	for _, cursor := range stmt.implicitResults {
+		_ = cursor.Close()
+	}
+	stmt.implicitResults = nil
+	err = stmt.setOutputs()
+	if err != nil {
+		return nil, err
+	}
 	result := new(QueryResult)
 	if session.Summary != nil {
 		result.rowsAffected = int64(session.Summary.CurRowNumber)
+		if stmt.stmtType == DML && result.rowsAffected > 0 {
+			rba, partitionID, blockNumber, slotNumber := session.Summary.RowID()
+			if rba != 0 || blockNumber != 0 || slotNumber != 0 {
+				id := rowid{
+					rba:         int64(rba),
+					partitionID: int64(partitionID),
+					blockNumber: int64(blockNumber),
+					slotNumber:  int64(slotNumber),
+				}
+				result.rowID = string(id.getBytes())
+			}
+		}
+	}
+	if stmt.arrayBindCount > 0 {
+		result.rowCounts = stmt.arrayRowCounts
+		if stmt.batchOption != nil && stmt.batchOption.RowCounts != nil {
+			*stmt.batchOption.RowCounts = stmt.arrayRowCounts
+		}
+		if err = stmt.batchError(result); err != nil {
+			return nil, err
+		}
 	}
 	return result, nil
 }
 
+// batchError return BatchError when iterations failed in continue on error
+// mode
+func (stmt *Stmt) batchError(result *QueryResult) error {
+	summary := stmt.connection.session.Summary
+	if summary == nil || len(summary.BindErrors) == 0 {
+		return nil
+	}
+	ret := &BatchError{
+		Errors:       make([]IterationError, 0, len(summary.BindErrors)),
+		RowsAffected: result.rowsAffected,
+		RowCounts:    result.rowCounts,
+	}
+	for _, bindErr := range summary.BindErrors {
+		ret.Errors = append(ret.Errors, IterationError{
+			Iteration: bindErr.RowOffset,
+			ErrCode:   bindErr.ErrorCode,
+			ErrMsg:    stmt.connection.strConv.Decode(bindErr.ErrorMsg),
+		})
+	}
+	return ret
+}
+
+func (stmt *Stmt) ExecContext(ctx context.Context, namedArgs []driver.NamedValue) (driver.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	call := stmt.connection.session.StartContext(ctx)
+	result, err := stmt.exec(namedArgs)
+	if endErr := stmt.connection.session.EndContext(call); endErr != nil && err == nil {
+		err = endErr
+	}
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return result, err
+}
+
+// CheckNamedValue accept all values as they are converted in NewParam.
+// sql.Out and Out are used for output parameters
 func (stmt *Stmt) CheckNamedValue(named *driver.NamedValue) error {
 	return nil
 }
 
+// bindArgs convert arguments into stmt.Pars. named arguments are bound to
+// the placeholders with the same name (case insensitive) and positional
+// arguments are bound in order
+func (stmt *Stmt) bindArgs(args []driver.NamedValue) error {
+	if stmt.paramError != nil {
+		return stmt.paramError
+	}
+	err := stmt.freeTemporaryLobs()
+	if err != nil {
+		return err
+	}
+	var option *BatchOption
+	args, option = takeBatchOption(args)
+	if (option != nil && option.ContinueOnError) != (stmt.batchOption != nil && stmt.batchOption.ContinueOnError) {
+		// execute options are sent with parameter definitions
+		stmt.reSendParDef = true
+	}
+	stmt.batchOption = option
+	stmt.arrayRowCounts = nil
+	named := 0
+	for _, arg := range args {
+		if len(arg.Name) > 0 {
+			named++
+		}
+	}
+	if named > 0 {
+		if named < len(args) {
+			return errors.New("mixing positional and named arguments is not allowed")
+		}
+		var err error
+		args, err = orderNamedArgs(parseBindNames(stmt.text, stmt.stmtType == PLSQL), args)
+		if err != nil {
+			return err
+		}
+	}
+	if stmt.stmtType != DML {
+		args = vectorArgs(args)
+	}
+	arrayCount, err := arrayBindSize(args)
+	if err != nil {
+		return err
+	}
+	if arrayCount > 0 && stmt.stmtType != DML {
+		return errors.New("array binding is supported only for INSERT, UPDATE and DELETE")
+	}
+	if arrayCount > 0 && stmt._hasReturnClause {
+		return errors.New("array binding is not supported with returning clause")
+	}
+	if arrayCount != stmt.arrayBindCount {
+		// dml row counts are requested with parameter definitions
+		stmt.reSendParDef = true
+		stmt.arrayBindCount = arrayCount
+	}
+	for x := 0; x < len(args); x++ {
+		var par ParameterInfo
+		if arrayCount > 0 {
+			temp, err := stmt.newArrayParam(args[x].Name, args[x].Value)
+			if err != nil {
+				return err
+			}
+			par = *temp
+		} else {
+			switch val := args[x].Value.(type) {
+			case sql.Out:
+				temp, err := stmt.newOutParam(args[x].Name, Out{Dest: val.Dest, In: val.In})
+				if err != nil {
+					return err
+				}
+				par = *temp
+			case Out:
+				temp, err := stmt.newOutParam(args[x].Name, val)
+				if err != nil {
+					return err
+				}
+				par = *temp
+			default:
+				temp, err := stmt.newParam(args[x].Name, args[x].Value, 0, Input)
+				if err != nil {
+					return err
+				}
+				par = *temp
+			}
+		}
+		if x < len(stmt.Pars) {
+			if par.MaxLen > stmt.Pars[x].MaxLen || par.DataType != stmt.Pars[x].DataType {
+				stmt.reSendParDef = true
+			}
+			stmt.Pars[x] = par
+		} else {
+			stmt.Pars = append(stmt.Pars, par)
+		}
+		stmt.connection.connOption.Tracer.Printf("    %d:\n%v", x, args[x].Value)
+	}
+	return nil
+}
+
+var utcZone = time.FixedZone("UTC", 0)
+
+// newArrayParam create parameter definition that fit all elements of the
+// slice and keep encoded value of each element for array binding
+func (stmt *Stmt) newArrayParam(name string, val driver.Value) (*ParameterInfo, error) {
+	rValue := reflect.ValueOf(val)
+	values := make([][]byte, rValue.Len())
+	elems := make([]driver.Value, rValue.Len())
+	hasZone := false
+	for x := 0; x < rValue.Len(); x++ {
+		elem, err := arrayElemValue(rValue.Index(x).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if ti, ok := elem.(time.Time); ok && hasTimeZone(ti) {
+			hasZone = true
+		}
+		elems[x] = elem
+	}
+	toLob := arrayNeedsLob(elems)
+	var par *ParameterInfo
+	for x, elem := range elems {
+		if ti, ok := elem.(time.Time); ok && hasZone && !hasTimeZone(ti) {
+			// all elements are bound as TIMESTAMP WITH TIME ZONE
+			elem = ti.In(utcZone)
+		}
+		var temp *ParameterInfo
+		var err error
+		if toLob {
+			// all strings and []byte are bound as temporary LOB. empty one
+			// is NULL as when it is bound inline
+			switch value := elem.(type) {
+			case string:
+				if len(value) == 0 {
+					elem = nil
+				} else {
+					temp, err = stmt.newTempLobParam(name, strings.NewReader(value), true)
+				}
+			case []byte:
+				if len(value) == 0 {
+					elem = nil
+				} else {
+					temp, err = stmt.newTempLobParam(name, bytes.NewReader(value), false)
+				}
+			}
+		}
+		if temp == nil && err == nil {
+			temp, err = stmt.newParam(name, elem, 0, Input)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if temp.DataType == 0 {
+			return nil, fmt.Errorf("unsupported type %T for array binding", elem)
+		}
+		values[x] = temp.BValue
+		if elem == nil {
+			continue
+		}
+		if par == nil {
+			par = temp
+			continue
+		}
+		if temp.DataType != par.DataType {
+			return nil, fmt.Errorf("array elements of type %T and %T cannot be bound together",
+				rValue.Index(0).Interface(), rValue.Index(x).Interface())
+		}
+		if temp.MaxLen > par.MaxLen {
+			par.MaxLen = temp.MaxLen
+		}
+		if temp.MaxCharLen > par.MaxCharLen {
+			par.MaxCharLen = temp.MaxCharLen
+		}
+	}
+	if par == nil {
+		// nil value is always accepted
+		par, _ = stmt.newParam(name, nil, 0, Input)
+	}
+	par.BValue = nil
+	par.arrayBValues = values
+	return par, nil
+}
+
+// arrayNeedsLob return true when a string or []byte element is too large to
+// be sent inline so the whole array is bound as LOB
+func arrayNeedsLob(elems []driver.Value) bool {
+	for _, elem := range elems {
+		switch value := elem.(type) {
+		case string:
+			if len(value) > maxInlineSize {
+				return true
+			}
+		case []byte:
+			if len(value) > maxInlineSize {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (stmt *Stmt) newOutParam(name string, out Out) (*ParameterInfo, error) {
+	switch out.Dest.(type) {
+	case *driver.Rows, *RefCursor:
+		if out.In {
+			return nil, errors.New("REF CURSOR parameter cannot be input")
+		}
+		par := stmt.newRefCursorParam(name)
+		par.outDest = out.Dest
+		return par, nil
+	}
+	val, err := outValue(out.Dest, out.In)
+	if err != nil {
+		return nil, err
+	}
+	direction := Output
+	if out.In {
+		direction = InOut
+	}
+	size := out.Size
+	if size == 0 {
+		switch val.(type) {
+		case string, NVarChar, []byte:
+			size = defaultOutSize
+		}
+	}
+	par, err := stmt.newParam(name, val, size, direction)
+	if err != nil {
+		return nil, err
+	}
+	par.outDest = out.Dest
+	if isArrayBind(reflect.ValueOf(out.Dest).Elem().Interface()) {
+		if !stmt._hasReturnClause {
+			return nil, errors.New("slice output destination is supported only for returning clause")
+		}
+		par.returnArray = true
+	}
+	return par, nil
+}
+
+// setOutputs copy output parameter values into sql.Out destinations
+func (stmt *Stmt) setOutputs() error {
+	for x := 0; x < len(stmt.Pars); x++ {
+		par := &stmt.Pars[x]
+		if par.outDest == nil || par.Direction == Input {
+			continue
+		}
+		if cursor, ok := par.Value.(RefCursor); ok {
+			err := setOutCursor(par.outDest, cursor)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if par.returnArray {
+			values, _ := par.Value.([]driver.Value)
+			err := setOutSlice(par.outDest, values)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err := setOutValue(par.outDest, par.Value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewParam create parameter for the value. values that cannot be encoded
+// return NULL parameter and the error is returned when the statement is
+// executed
 func (stmt *Stmt) NewParam(name string, val driver.Value, size int, direction ParameterDirection) *ParameterInfo {
+	par, err := stmt.newParam(name, val, size, direction)
+	if err != nil {
+		stmt.paramError = err
+		// nil value is always accepted
+		par, _ = stmt.newParam(name, nil, size, direction)
+	}
+	return par
+}
+
+func (stmt *Stmt) newParam(name string, val driver.Value, size int, direction ParameterDirection) (*ParameterInfo, error) {
+	var err error
 	param := &ParameterInfo{
 		Name:        name,
 		Direction:   direction,
@@ -1103,18 +1796,155 @@ func (stmt *Stmt) NewParam(name string, val driver.Value, size int, direction Pa
 		case int:
 			param.BValue = converters.EncodeInt(val)
 			param.DataType = NUMBER
+		case uint64:
+			param.BValue = converters.EncodeUint64(val)
+			param.DataType = NUMBER
+		case uint32:
+			param.BValue = converters.EncodeUint64(uint64(val))
+			param.DataType = NUMBER
+		case uint16:
+			param.BValue = converters.EncodeUint64(uint64(val))
+			param.DataType = NUMBER
+		case uint8:
+			param.BValue = converters.EncodeUint64(uint64(val))
+			param.DataType = NUMBER
+		case uint:
+			param.BValue = converters.EncodeUint64(uint64(val))
+			param.DataType = NUMBER
+		case bool:
+			if stmt.connection.nativeBoolean() || stmt.stmtType == PLSQL {
+				// PL/SQL BOOLEAN is accepted by older servers
+				param.BValue = converters.EncodeBool(val)
+				param.DataType = BOOLEAN
+				param.ContFlag = 0
+				param.MaxLen = 4
+				param.MaxCharLen = 0
+				param.CharsetForm = 0
+			} else if val {
+				param.BValue = converters.EncodeInt(1)
+				param.DataType = NUMBER
+			} else {
+				param.BValue = converters.EncodeInt(0)
+				param.DataType = NUMBER
+			}
 		case float32:
 			param.BValue, _ = converters.EncodeDouble(float64(val))
 			param.DataType = NUMBER
 		case float64:
 			param.BValue, _ = converters.EncodeDouble(val)
 			param.DataType = NUMBER
+		case Decimal:
+			param.BValue, err = val.encode()
+			param.DataType = NUMBER
+		case *big.Int:
+			param.DataType = NUMBER
+			if val == nil {
+				param.BValue = nil
+			} else {
+				param.BValue, err = converters.NumberFromString(val.String())
+			}
+		case *big.Float:
+			param.DataType = NUMBER
+			if val == nil {
+				param.BValue = nil
+			} else if val.IsInf() {
+				err = errors.New("cannot bind infinite *big.Float as NUMBER")
+			} else {
+				param.BValue, err = converters.NumberFromString(val.Text('e', -1))
+				if err != nil {
+					// shortest text of high precision float may exceed NUMBER digits
+					param.BValue, err = converters.NumberFromString(val.Text('e', 37))
+				}
+			}
 		case time.Time:
-			param.BValue = converters.EncodeDate(val)
+			if hasTimeZone(val) {
+				// keep the offset
+				param.BValue = converters.EncodeTimeStampTZ(val)
+				param.DataType = TimeStampTZ_DTY
+				param.ContFlag = 0
+				param.MaxLen = 13
+				param.MaxCharLen = 0
+				param.CharsetForm = 0
+			} else {
+				param.BValue = converters.EncodeTimeStamp(val)
+				param.DataType = TimeStampDTY
+				param.ContFlag = 0
+				param.MaxLen = 11
+				param.MaxCharLen = 0
+				param.CharsetForm = 0
+			}
+		case Date:
+			param.BValue = converters.EncodeDate(time.Time(val))
 			param.DataType = DATE
 			param.ContFlag = 0
 			param.MaxLen = 11
 			param.MaxCharLen = 11
+		case BinaryFloat:
+			param.BValue = converters.EncodeBinaryFloat(float32(val))
+			param.DataType = IBFloat
+			param.ContFlag = 0
+			param.MaxLen = 4
+			param.MaxCharLen = 0
+			param.CharsetForm = 0
+		case BinaryDouble:
+			param.BValue = converters.EncodeBinaryDouble(float64(val))
+			param.DataType = IBDouble
+			param.ContFlag = 0
+			param.MaxLen = 8
+			param.MaxCharLen = 0
+			param.CharsetForm = 0
+		case time.Duration:
+			param.BValue = converters.EncodeIntervalDS(val)
+			param.DataType = IntervalDS_DTY
+			param.ContFlag = 0
+			param.MaxLen = 11
+			param.MaxCharLen = 0
+			param.CharsetForm = 0
+		case converters.IntervalYM:
+			param.BValue = converters.EncodeIntervalYM(val)
+			param.DataType = IntervalYM_DTY
+			param.ContFlag = 0
+			param.MaxLen = 5
+			param.MaxCharLen = 0
+			param.CharsetForm = 0
+		case json.RawMessage:
+			param.BValue, err = encodeJSONText(val)
+			setJSONParam(param)
+		case JSONValue:
+			param.BValue, err = val.encode()
+			setJSONParam(param)
+		case map[string]interface{}:
+			param.BValue, err = converters.EncodeOSON(val)
+			setJSONParam(param)
+		case XML:
+			param.DataType = XMLType
+			param.TypeName = "XMLTYPE"
+			param.IsXmlType = true
+			param.ToID = xmlTypeOID
+			param.Version = 1
+			param.ContFlag = 0
+			param.MaxCharLen = 0
+			param.CharsetForm = 0
+			param.MaxLen = 4000
+			param.BValue = converters.EncodeXMLImage([]byte(val.Text))
+		case *LobLocator:
+			if val == nil {
+				return stmt.newParam(name, nil, size, direction)
+			}
+			err = val.Flush()
+			setLobParam(param, val)
+		case io.Reader:
+			if direction != Input {
+				return nil, errors.New("io.Reader can be bound only as input parameter")
+			}
+			return stmt.newTempLobParam(name, val, false)
+		case Vector:
+			param.BValue, err = val.encode()
+			param.DataType = VECTOR
+			param.ContFlag = 0x2000000
+			param.MaxLen = 1024 * 1024
+			param.MaxCharLen = 0
+			param.CharsetForm = 0
 		//case ParameterInfo:
 		//	fmt.Println("parameter info")
 
@@ -1138,6 +1968,9 @@ func (stmt *Stmt) NewParam(name string, val driver.Value, size int, direction Pa
 				param.MaxLen = param.MaxCharLen * converters.MaxBytePerChar(param.CharsetID)
 			}
 		case string:
+			if direction == Input && len(val) > maxInlineSize {
+				return stmt.newTempLobParam(name, strings.NewReader(val), true)
+			}
 			param.DataType = NCHAR
 			param.ContFlag = 16
 			param.MaxCharLen = len([]rune(val))
@@ -1156,12 +1989,43 @@ func (stmt *Stmt) NewParam(name string, val driver.Value, size int, direction Pa
 				param.MaxLen = param.MaxCharLen * converters.MaxBytePerChar(param.CharsetID)
 			}
 		case []byte:
+			if direction == Input && len(val) > maxInlineSize {
+				return stmt.newTempLobParam(name, bytes.NewReader(val), false)
+			}
 			param.BValue = val
 			param.DataType = RAW
 			param.MaxLen = len(val)
+			if size > len(val) {
+				param.MaxLen = size
+			}
 			param.ContFlag = 0
 			param.MaxCharLen = 0
 			param.CharsetForm = 0
+		default:
+			rValue := reflect.ValueOf(val)
+			if rValue.Kind() == reflect.Ptr && rValue.IsNil() {
+				return stmt.newParam(name, nil, size, direction)
+			}
+			if valuer, ok := val.(driver.Valuer); ok {
+				temp, err := valuer.Value()
+				if err != nil {
+					return nil, err
+				}
+				return stmt.newParam(name, temp, size, direction)
+			}
+			if rValue.Kind() == reflect.Ptr {
+				return stmt.newParam(name, rValue.Elem().Interface(), size, direction)
+			}
+			switch rValue.Kind() {
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return stmt.newParam(name, rValue.Uint(), size, direction)
+			}
+			// named types of basic kinds like "type Status int"
+			temp, err := driver.DefaultParameterConverter.ConvertValue(val)
+			if err != nil {
+				return nil, fmt.Errorf("unsupported parameter type %T: %v", val, err)
+			}
+			return stmt.newParam(name, temp, size, direction)
 		}
 		if param.DataType == NUMBER {
 			param.ContFlag = 0
@@ -1173,18 +2037,130 @@ func (stmt *Stmt) NewParam(name string, val driver.Value, size int, direction Pa
 			param.BValue = nil
 		}
 	}
-	return param
+	if err != nil {
+		return nil, err
+	}
+	return param, nil
+}
+
+// maxInlineSize is the largest string or []byte value sent inline. larger
+// values are written into temporary LOB
+const maxInlineSize = 32767
+
+// newTempLobParam copy data into temporary LOB and bind it. the LOB is
+// freed when the statement is executed again or closed
+func (stmt *Stmt) newTempLobParam(name string, data io.Reader, isClob bool) (*ParameterInfo, error) {
+	lob, err := NewTempLob(stmt.connection, isClob)
+	if err != nil {
+		return nil, err
+	}
+	stmt.temporaryLobs = append(stmt.temporaryLobs, lob)
+	_, err = io.Copy(lob, data)
+	if err != nil {
+		return nil, err
+	}
+	param := &ParameterInfo{
+		Name:      name,
+		Direction: Input,
+		Flag:      3,
+		CharsetID: stmt.connection.tcpNego.ServerCharset,
+	}
+	setLobParam(param, lob)
+	return param, nil
+}
+
+// freeTemporaryLobs free temporary LOBs created for the previous execution
+func (stmt *Stmt) freeTemporaryLobs() error {
+	var err error
+	for _, lob := range stmt.temporaryLobs {
+		if tempErr := lob.free(); tempErr != nil && err == nil {
+			err = tempErr
+		}
+	}
+	stmt.temporaryLobs = nil
+	return err
+}
+
+// setLobParam set definition of BLOB or CLOB parameter. the value is the
+// locator
+func setLobParam(param *ParameterInfo, lob *LobLocator) {
+	param.DataType = OCIBlobLocator
+	param.CharsetForm = 0
+	if lob.isClob {
+		param.DataType = OCIClobLocator
+		param.CharsetForm = 1
+	}
+	param.ContFlag = 0x2000000
+	param.MaxLen = 112
+	param.MaxCharLen = 0
+	param.BValue = lob.lob.sourceLocator
+}
+
+// hasTimeZone return true when val has a zone other than UTC so it is sent
+// as TIMESTAMP WITH TIME ZONE to keep its offset
+func hasTimeZone(val time.Time) bool {
+	name, offset := val.Zone()
+	return offset != 0 || (name != "UTC" && name != "")
 }
-func (stmt *Stmt) AddParam(name string, val driver.Value, size int, direction ParameterDirection) {
-	stmt.Pars = append(stmt.Pars, *stmt.NewParam(name, val, size, direction))
 
+// setJSONParam set definition of JSON parameter. the value is sent as
+// OSON image
+func setJSONParam(param *ParameterInfo) {
+	param.DataType = JSON
+	param.ContFlag = 0x2000000
+	param.MaxLen = 0x2000000
+	param.MaxCharLen = 0
+	param.CharsetForm = 0
+}
+
+// AddParam add parameter to the statement. for returning clause pass
+// output parameter with slice value (like []int64{}) to receive all
+// affected rows in Value as []driver.Value. values that cannot be encoded
+// are not added and the error is returned when the statement is executed
+func (stmt *Stmt) AddParam(name string, val driver.Value, size int, direction ParameterDirection) {
+	returnArray := direction == Output && isArrayBind(val)
+	if returnArray {
+		val = reflect.Zero(reflect.TypeOf(val).Elem()).Interface()
+	}
+	par, err := stmt.newParam(name, val, size, direction)
+	if err != nil {
+		stmt.paramError = err
+		return
+	}
+	par.returnArray = returnArray
+	stmt.Pars = append(stmt.Pars, *par)
 }
 func (stmt *Stmt) AddRefCursorParam(name string) {
-	par := stmt.NewParam("1", nil, 0, Output)
+	stmt.Pars = append(stmt.Pars, *stmt.newRefCursorParam(name))
+}
+
+func (stmt *Stmt) newRefCursorParam(name string) *ParameterInfo {
+	// nil value is always accepted
+	par, _ := stmt.newParam(name, nil, 0, Output)
 	par.DataType = REFCURSOR
 	par.ContFlag = 0
 	par.CharsetForm = 0
-	stmt.Pars = append(stmt.Pars, *par)
+	return par
+}
+
+// setOutCursor copy REF CURSOR into sql.Out destination. driver.Rows
+// destination is executed here so the first rows are fetched in the same
+// call
+func setOutCursor(dest interface{}, cursor RefCursor) error {
+	switch dest := dest.(type) {
+	case *RefCursor:
+		*dest = cursor
+	case *driver.Rows:
+		dataSet, err := cursor.Query()
+		if err != nil {
+			return err
+		}
+		cursor.dataSet = dataSet
+		*dest = &cursor
+	default:
+		return fmt.Errorf("cannot assign REF CURSOR to output destination of type %T", dest)
+	}
+	return nil
 }
 
 //func (stmt *Stmt) AddParam(name string, val driver.BValue, size int, direction ParameterDirection) {
@@ -1267,19 +2243,16 @@ func (stmt *Stmt) AddRefCursorParam(name string) {
 //
 //}
 func (stmt *Stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return stmt.query(toNamedValues(args))
+}
+
+func (stmt *Stmt) query(args []driver.NamedValue) (driver.Rows, error) {
 	stmt.connection.connOption.Tracer.Printf("Query:\n%s", stmt.text)
 	stmt._noOfRowsToFetch = stmt.connection.connOption.PrefetchRows
 	stmt._hasMoreRows = true
-	for x := 0; x < len(args); x++ {
-		par := *stmt.NewParam("", args[x], 0, Input)
-		if x < len(stmt.Pars) {
-			if par.MaxLen > stmt.Pars[x].MaxLen {
-				stmt.reSendParDef = true
-			}
-			stmt.Pars[x] = par
-		} else {
-			stmt.Pars = append(stmt.Pars, par)
-		}
+	err := stmt.bindArgs(args)
+	if err != nil {
+		return nil, err
 	}
 	//stmt.Pars = nil
 	//for x := 0; x < len(args); x++ {
@@ -1287,7 +2260,7 @@ func (stmt *Stmt) Query(args []driver.Value) (driver.Rows, error) {
 	//}
 	stmt.connection.session.ResetBuffer()
 	// if re-execute
-	err := stmt.write(stmt.connection.session)
+	err = stmt.write(stmt.connection.session)
 	if err != nil {
 		return nil, err
 	}
@@ -1296,13 +2269,49 @@ func (stmt *Stmt) Query(args []driver.Value) (driver.Rows, error) {
 	//	return nil, err
 	//}
 	dataSet := new(DataSet)
+	if len(stmt.columns) > 0 {
+		// re-execute or cached cursor: the server may not send column
+		// definitions again
+		dataSet.ColumnCount = len(stmt.columns)
+		dataSet.Cols = make([]ParameterInfo, len(stmt.columns))
+		copy(dataSet.Cols, stmt.columns)
+	}
+	stmt.implicitResults = nil
 	err = stmt.read(dataSet)
 	if err != nil {
 		return nil, err
 	}
+	if len(stmt.implicitResults) > 0 {
+		dataSet.results = stmt.implicitResults
+		stmt.implicitResults = nil
+		err = dataSet.nextResult()
+		if err != nil {
+			_ = dataSet.Close()
+			return nil, err
+		}
+	}
 	return dataSet, nil
 }
 
+func (stmt *Stmt) QueryContext(ctx context.Context, namedArgs []driver.NamedValue) (driver.Rows, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	call := stmt.connection.session.StartContext(ctx)
+	rows, err := stmt.query(namedArgs)
+	if endErr := stmt.connection.session.EndContext(call); endErr != nil && err == nil {
+		err = endErr
+	}
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if dataSet, ok := rows.(*DataSet); ok && ctx.Done() != nil {
+		// following fetches are interrupted by the same context
+		dataSet.ctx = ctx
+	}
+	return rows, err
+}
+
 func (stmt *Stmt) NumInput() int {
 	return -1
 }
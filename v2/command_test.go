@@ -0,0 +1,282 @@
+package go_ora
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewParamType(t *testing.T) {
+	stmt := NewStmt("INSERT INTO T1 VALUES(:1)", newTestConnection())
+	tests := []struct {
+		value driver.Value
+		want  OracleType
+	}{
+		{nil, NCHAR},
+		{int64(1), NUMBER},
+		{int8(1), NUMBER},
+		{uint64(1), NUMBER},
+		{1.5, NUMBER},
+		{true, NUMBER},
+		{"a", NCHAR},
+		{NVarChar("a"), NCHAR},
+		{[]byte{1}, RAW},
+		{time.Now().UTC(), TimeStampDTY},
+		{time.Now().In(time.FixedZone("UTC", 0)), TimeStampDTY},
+		{time.Now().In(time.FixedZone("", 3600)), TimeStampTZ_DTY},
+		{time.Now().In(time.FixedZone("GMT", 0)), TimeStampTZ_DTY},
+		{Date(time.Now()), DATE},
+		{sql.NullString{String: "a", Valid: true}, NCHAR},
+		{sql.NullInt64{Int64: 1, Valid: true}, NUMBER},
+		{(*int)(nil), NCHAR},
+	}
+	for _, test := range tests {
+		par, err := stmt.newParam("", test.value, 0, Input)
+		if err != nil {
+			t.Errorf("newParam(%T) unexpected error: %s", test.value, err)
+			continue
+		}
+		if par.DataType != test.want {
+			t.Errorf("newParam(%T) bound as %v, want %v", test.value, par.DataType, test.want)
+		}
+	}
+	if _, err := stmt.newParam("", struct{}{}, 0, Input); err == nil {
+		t.Errorf("newParam expected error for unsupported type")
+	}
+}
+
+func TestBoolParamType(t *testing.T) {
+	tests := []struct {
+		serverVersion uint8
+		plsql         bool
+		want          OracleType
+	}{
+		{11, false, NUMBER},
+		{11, true, BOOLEAN},
+		{ttcFieldVersion23, false, BOOLEAN},
+		{24, false, BOOLEAN},
+	}
+	for _, test := range tests {
+		conn := newTestConnection()
+		caps := make([]byte, 41)
+		caps[7] = test.serverVersion
+		conn.tcpNego.ServerCompileTimeCaps = caps
+		conn.session.TTCVersion = buildTypeNego(conn.tcpNego, conn.session).fieldVersion()
+		text := "INSERT INTO T1 VALUES(:1)"
+		if test.plsql {
+			text = "BEGIN P1(:1); END;"
+		}
+		stmt := NewStmt(text, conn)
+		par, err := stmt.newParam("", true, 0, Input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if par.DataType != test.want {
+			t.Errorf("server field version %d: bool bound as %v, want %v", test.serverVersion, par.DataType, test.want)
+		}
+		if par.DataType == BOOLEAN && !reflect.DeepEqual(par.BValue, []byte{1, 1}) {
+			t.Errorf("server field version %d: bool encoded as %v", test.serverVersion, par.BValue)
+		}
+	}
+}
+
+func TestTypeNegoFieldVersion(t *testing.T) {
+	tests := []struct {
+		serverVersion uint8
+		want          uint8
+		vector        bool
+	}{
+		{12, 11, false},
+		{ttcFieldVersion23, ttcFieldVersion23, false},
+		{20, ttcFieldVersion23, false},
+		{ttcFieldVersionVector, ttcFieldVersionVector, true},
+		{25, ttcFieldVersionVector, true},
+	}
+	for _, test := range tests {
+		caps := make([]byte, 41)
+		caps[7] = test.serverVersion
+		nego := buildTypeNego(&TCPNego{ServerCompileTimeCaps: caps}, newTestConnection().session)
+		if version := nego.fieldVersion(); version != test.want {
+			t.Errorf("server field version %d: negotiated %d, want %d", test.serverVersion, version, test.want)
+		}
+		vector := len(nego.CompileTimeCaps) > 52 && nego.CompileTimeCaps[44]&8 != 0
+		if vector != test.vector {
+			t.Errorf("server field version %d: vector support advertised %v", test.serverVersion, vector)
+		}
+	}
+}
+
+func TestArrayBindSize(t *testing.T) {
+	named := func(values ...driver.Value) []driver.NamedValue {
+		return toNamedValues(values)
+	}
+	tests := []struct {
+		args    []driver.NamedValue
+		want    int
+		wantErr bool
+	}{
+		{named(int64(1), "a"), 0, false},
+		{named([]byte{1, 2}), 0, false},
+		{named([]int64{1, 2}, []string{"a", "b"}), 2, false},
+		{named([]int64{1, 2}, []string{"a"}), 0, true},
+		{named([]int64{1, 2}, "a"), 0, true},
+		{named([]int64{}), 0, true},
+	}
+	for _, test := range tests {
+		got, err := arrayBindSize(test.args)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("arrayBindSize(%v) = %d, %v", test.args, got, err)
+		}
+	}
+}
+
+func TestArrayElemValue(t *testing.T) {
+	text := "a"
+	tests := []struct {
+		value interface{}
+		want  driver.Value
+	}{
+		{nil, nil},
+		{int64(1), int64(1)},
+		{"a", "a"},
+		{&text, "a"},
+		{(*string)(nil), nil},
+		{sql.NullString{String: "b", Valid: true}, "b"},
+		{sql.NullInt64{}, nil},
+	}
+	for _, test := range tests {
+		got, err := arrayElemValue(test.value)
+		if err != nil {
+			t.Errorf("arrayElemValue(%v) unexpected error: %s", test.value, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("arrayElemValue(%v) = %v, want %v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestNewArrayParam(t *testing.T) {
+	stmt := NewStmt("INSERT INTO T1 VALUES(:1)", newTestConnection())
+	par, err := stmt.newArrayParam("", []interface{}{nil, "ab", "abcd"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if par.DataType != NCHAR || len(par.arrayBValues) != 3 || par.arrayBValues[0] != nil ||
+		par.MaxCharLen != 4 {
+		t.Errorf("newArrayParam = %v %d %v", par.DataType, par.MaxCharLen, par.arrayBValues)
+	}
+	if _, err = stmt.newArrayParam("", []interface{}{int64(1), "a"}); err == nil {
+		t.Errorf("newArrayParam expected error for mixed element types")
+	}
+}
+
+func TestArrayNeedsLob(t *testing.T) {
+	large := strings.Repeat("a", maxInlineSize+1)
+	tests := []struct {
+		elems []driver.Value
+		want  bool
+	}{
+		{[]driver.Value{"a", nil, ""}, false},
+		{[]driver.Value{"a", large[:maxInlineSize]}, false},
+		{[]driver.Value{"a", nil, large}, true},
+		{[]driver.Value{[]byte{1}, []byte(large)}, true},
+		{[]driver.Value{int64(1), nil}, false},
+	}
+	for _, test := range tests {
+		if got := arrayNeedsLob(test.elems); got != test.want {
+			t.Errorf("arrayNeedsLob with %d elements = %v, want %v", len(test.elems), got, test.want)
+		}
+	}
+}
+
+func TestAddParamUnsupported(t *testing.T) {
+	stmt := NewStmt("INSERT INTO T1 VALUES(:1) RETURNING ID INTO :2", newTestConnection())
+	stmt.AddParam("2", []int64{}, 0, Output)
+	if len(stmt.Pars) != 1 || !stmt.Pars[0].returnArray || stmt.Pars[0].DataType != NUMBER {
+		t.Errorf("AddParam returning slice = %v", stmt.Pars)
+	}
+	if err := stmt.bindArgs(nil); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	stmt.AddParam("1", struct{}{}, 0, Input)
+	if len(stmt.Pars) != 1 {
+		t.Errorf("expected unsupported parameter not to be added, got %d parameters", len(stmt.Pars))
+	}
+	if err := stmt.bindArgs(nil); err == nil {
+		t.Errorf("AddParam error expected on execution")
+	}
+	stmt = NewStmt("INSERT INTO T1 VALUES(:1)", newTestConnection())
+	par := stmt.NewParam("1", struct{}{}, 0, Input)
+	if par == nil || par.Value != nil {
+		t.Errorf("NewParam expected NULL parameter for unsupported type, got %v", par)
+	}
+	if err := stmt.bindArgs(nil); err == nil {
+		t.Errorf("NewParam error expected on execution")
+	}
+}
+
+func TestBindPlainVector(t *testing.T) {
+	args := []driver.NamedValue{{Ordinal: 1, Value: []float32{0.5, 1, 2}}}
+	stmt := NewStmt("SELECT ID FROM T1 ORDER BY VECTOR_DISTANCE(V, :1)", newTestConnection())
+	if err := stmt.bindArgs(args); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(stmt.Pars) != 1 || stmt.Pars[0].DataType != VECTOR || stmt.arrayBindCount != 0 {
+		t.Errorf("query bound []float32 as %v with array count %d", stmt.Pars[0].DataType, stmt.arrayBindCount)
+	}
+	if _, ok := args[0].Value.([]float32); !ok {
+		t.Errorf("caller arguments modified: %T", args[0].Value)
+	}
+	stmt = NewStmt("INSERT INTO T1(V) VALUES(:1)", newTestConnection())
+	if err := stmt.bindArgs(args); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if stmt.arrayBindCount != 3 || stmt.Pars[0].DataType == VECTOR {
+		t.Errorf("DML bound []float32 as %v with array count %d", stmt.Pars[0].DataType, stmt.arrayBindCount)
+	}
+}
+
+func TestHasReturnClause(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"INSERT INTO T1(ID) VALUES(1) RETURNING ID INTO :id", true},
+		{"update t1 set a = 1 returning rowid into :1", true},
+		{"INSERT INTO T1(NAME) VALUES('RETURNING')", false},
+		{`SELECT "RETURNING" FROM T1`, false},
+		{"DELETE FROM T1 -- RETURNING\n", false},
+		{"DELETE FROM T1 /* RETURNING */", false},
+		{"UPDATE T1 SET RETURNING_DATE = SYSDATE", false},
+		{"UPDATE T1 SET A = 'x'RETURNING A INTO :1", true},
+	}
+	for _, test := range tests {
+		if got := hasReturnClause(test.text); got != test.want {
+			t.Errorf("hasReturnClause(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestTakeBatchOption(t *testing.T) {
+	var counts []int64
+	args := toNamedValues([]driver.Value{int64(1), BatchOption{ContinueOnError: true, RowCounts: &counts}, "a"})
+	rest, option := takeBatchOption(args)
+	if option == nil || !option.ContinueOnError || len(rest) != 2 || rest[1].Value != "a" {
+		t.Errorf("takeBatchOption = %v, %v", rest, option)
+	}
+	rest, option = takeBatchOption(toNamedValues([]driver.Value{int64(1)}))
+	if option != nil || len(rest) != 1 {
+		t.Errorf("takeBatchOption without option = %v, %v", rest, option)
+	}
+}
+
+func TestRowIDString(t *testing.T) {
+	id := rowid{rba: 73196, partitionID: 4, blockNumber: 151, slotNumber: 0}
+	if got := string(id.getBytes()); got != "AAAR3sAAEAAAACXAAA" {
+		t.Errorf("rowid = %s, want AAAR3sAAEAAAACXAAA", got)
+	}
+}
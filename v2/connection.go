@@ -10,6 +10,9 @@ import (
 	"os/user"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/sijms/go-ora/v2/advanced_nego"
 	"github.com/sijms/go-ora/v2/converters"
@@ -77,13 +80,29 @@ type Connection struct {
 	NLSData           NLSData
 	w                 *wallet
 	cusTyp            map[string]customType
+	closeTracer       bool
+	pool              *Pool
+	openTime          time.Time
+	stmtCache         *stmtCache
+	// lobLease change when the connection is released so LOB locators of
+	// the previous user are not used with the next one
+	lobLease int32
 }
 
+// Compile time Sentinels for implemented Interfaces.
+var _ = driver.ConnPrepareContext((*Connection)(nil))
+var _ = driver.ConnBeginTx((*Connection)(nil))
+var _ = driver.Pinger((*Connection)(nil))
+var _ = driver.Validator((*Connection)(nil))
+
 type OracleDriver struct {
+	// Deprecated: Conn hold the last opened connection only. use
+	// sql.Conn.Raw to reach the *Connection behind a database/sql connection
 	Conn    *Connection
 	Server  string
 	Service string
 	UserId  string
+	mu      sync.Mutex
 }
 
 func init() {
@@ -94,11 +113,32 @@ func (drv *OracleDriver) Open(name string) (driver.Conn, error) {
 	if err != nil {
 		return nil, err
 	}
+	err = conn.Open()
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	drv.setLastConn(conn)
+	return conn, nil
+}
+
+// OpenConnector parse the url once and return a connector that is used by
+// database/sql for all connections of the pool
+func (drv *OracleDriver) OpenConnector(name string) (driver.Connector, error) {
+	conStr, err := newConnectionStringFromUrl(name)
+	if err != nil {
+		return nil, err
+	}
+	return &OracleConnector{drv: drv, conStr: conStr}, nil
+}
+
+func (drv *OracleDriver) setLastConn(conn *Connection) {
+	drv.mu.Lock()
+	defer drv.mu.Unlock()
 	drv.Conn = conn
 	drv.Server = conn.connOption.Host
 	drv.Service = conn.connOption.ServiceName
 	drv.UserId = conn.connOption.UserID
-	return conn, conn.Open()
 }
 
 func (conn *Connection) SetStringConverter(converter converters.IStringConverter) {
@@ -219,14 +259,32 @@ func (conn *Connection) Prepare(query string) (driver.Stmt, error) {
 	return NewStmt(query, conn), nil
 }
 
-func (conn *Connection) Ping(_ context.Context) error {
+func (conn *Connection) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return conn.Prepare(query)
+}
+
+func (conn *Connection) Ping(ctx context.Context) error {
 	conn.connOption.Tracer.Print("Ping")
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	call := conn.session.StartContext(ctx)
 	conn.session.ResetBuffer()
-	return (&simpleObject{
+	err := (&simpleObject{
 		connection:  conn,
 		operationID: 0x93,
 		data:        nil,
 	}).write().read()
+	if endErr := conn.session.EndContext(call); endErr != nil && err == nil {
+		err = endErr
+	}
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
 }
 
 //func (conn *Connection) Logoff() error {
@@ -283,6 +341,10 @@ func (conn *Connection) Ping(_ context.Context) error {
 //}
 
 func (conn *Connection) Open() error {
+	return conn.OpenWithContext(context.Background())
+}
+
+func (conn *Connection) OpenWithContext(ctx context.Context) error {
 	tracer := conn.connOption.Tracer
 	tracer.Print("Open :", conn.connOption.ConnectionData())
 
@@ -307,7 +369,7 @@ func (conn *Connection) Open() error {
 	}
 
 	session := conn.session
-	err := session.Connect()
+	err := session.ConnectContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -356,11 +418,8 @@ func (conn *Connection) Open() error {
 	if err != nil {
 		return err
 	}
-	conn.session.TTCVersion = conn.dataNego.CompileTimeCaps[7]
+	conn.session.TTCVersion = conn.dataNego.fieldVersion()
 	conn.session.UseBigScn = conn.tcpNego.ServerCompileTimeCaps[7] >= 8
-	if conn.tcpNego.ServerCompileTimeCaps[7] < conn.session.TTCVersion {
-		conn.session.TTCVersion = conn.tcpNego.ServerCompileTimeCaps[7]
-	}
 	tracer.Print("TTC Version: ", conn.session.TTCVersion)
 	//this.m_b32kTypeSupported = this.m_dtyNeg.m_b32kTypeSupported;
 	//this.m_bSupportSessionStateOps = this.m_dtyNeg.m_bSupportSessionStateOps;
@@ -371,6 +430,7 @@ func (conn *Connection) Open() error {
 		return err
 	}
 	conn.State = Opened
+	conn.openTime = time.Now()
 	conn.dBVersion, err = GetDBVersion(conn.session)
 	if err != nil {
 		return err
@@ -407,12 +467,54 @@ func (conn *Connection) Begin() (driver.Tx, error) {
 	return &Transaction{conn: conn}, nil
 }
 
+func (conn *Connection) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var setTran string
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault:
+	case sql.LevelReadCommitted:
+		setTran = "SET TRANSACTION ISOLATION LEVEL READ COMMITTED"
+	case sql.LevelSerializable:
+		setTran = "SET TRANSACTION ISOLATION LEVEL SERIALIZABLE"
+	default:
+		return nil, fmt.Errorf("unsupported isolation level: %s", sql.IsolationLevel(opts.Isolation))
+	}
+	if opts.ReadOnly {
+		if len(setTran) > 0 {
+			return nil, errors.New("read only transaction cannot have isolation level")
+		}
+		setTran = "SET TRANSACTION READ ONLY"
+	}
+	tx, err := conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	if len(setTran) > 0 {
+		stmt := NewStmt(setTran, conn)
+		defer func(stmt *Stmt) {
+			_ = stmt.Close()
+		}(stmt)
+		_, err = stmt.ExecContext(ctx, nil)
+		if err != nil {
+			conn.autoCommit = true
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
 func NewConnection(databaseUrl string) (*Connection, error) {
 	//this.m_id = this.GetHashCode().ToString();
 	conStr, err := newConnectionStringFromUrl(databaseUrl)
 	if err != nil {
 		return nil, err
 	}
+	return newConnectionFromConnStr(conStr)
+}
+
+func newConnectionFromConnStr(conStr *ConnectionString) (*Connection, error) {
 	userName := ""
 	User, err := user.Current()
 	if err == nil {
@@ -450,14 +552,20 @@ func NewConnection(databaseUrl string) (*Connection, error) {
 			DriverName:  "OracleClientGo",
 			PID:         os.Getpid(),
 		},
-		SSL:       conStr.SSL,
+		SSL:       conStr.SSL || conStr.TLSConfig != nil,
 		SSLVerify: conStr.SSLVerify,
+		TLSConfig: conStr.TLSConfig,
+		Dialer:    conStr.Dialer,
 		//InAddrAny:             false,
 	}
-	if conStr.SSL {
+	if connOption.SSL {
 		connOption.Protocol = "tcps"
 	}
-	if len(conStr.Trace) > 0 {
+	closeTracer := true
+	if conStr.Tracer != nil {
+		connOption.Tracer = conStr.Tracer
+		closeTracer = false
+	} else if len(conStr.Trace) > 0 {
 		tf, err := os.Create(conStr.Trace)
 		if err != nil {
 			//noinspection GoErrorStringFormat
@@ -468,17 +576,25 @@ func NewConnection(databaseUrl string) (*Connection, error) {
 		connOption.Tracer = trace.NilTracer()
 	}
 	return &Connection{
-		State:      Closed,
-		conStr:     conStr,
-		connOption: connOption,
-		autoCommit: true,
-		w:          conStr.w,
-		cusTyp:     map[string]customType{},
+		State:       Closed,
+		conStr:      conStr,
+		connOption:  connOption,
+		autoCommit:  true,
+		w:           conStr.w,
+		cusTyp:      map[string]customType{},
+		closeTracer: closeTracer,
+		stmtCache:   newStmtCache(conStr.StmtCacheSize),
 	}, nil
 }
 
+// Close return pooled connection to its pool otherwise the connection is
+// closed
 func (conn *Connection) Close() (err error) {
+	if conn.pool != nil {
+		return conn.pool.put(conn)
+	}
 	conn.connOption.Tracer.Print("Close")
+	conn.releaseLobs()
 	//var err error = nil
 	if conn.session != nil {
 		//err = conn.Logoff()
@@ -486,14 +602,84 @@ func (conn *Connection) Close() (err error) {
 		conn.session = nil
 	}
 	conn.connOption.Tracer.Print("Connection Closed")
-	_ = conn.connOption.Tracer.Close()
+	if conn.closeTracer {
+		_ = conn.connOption.Tracer.Close()
+	}
 	return
 }
 
+// IsValid implement driver.Validator. it is called before the connection is
+// put back into the pool of database/sql or Pool so LOB locators of the
+// current user are released. connection with closed or broken session is
+// not reused by database/sql or returned to the pool
+func (conn *Connection) IsValid() bool {
+	conn.releaseLobs()
+	return conn.State == Opened && conn.session != nil && !conn.session.IsBroken()
+}
+
+// releaseLobs make LOB locators returned before the call fail
+func (conn *Connection) releaseLobs() {
+	atomic.AddInt32(&conn.lobLease, 1)
+}
+
+// purgeStmtCache close all server cursors kept in the statement cache
+func (conn *Connection) purgeStmtCache() error {
+	if conn.stmtCache == nil {
+		return nil
+	}
+	var err error
+	for _, item := range conn.stmtCache.purge() {
+		if tempErr := item.close(); tempErr != nil && err == nil {
+			err = tempErr
+		}
+	}
+	return err
+}
+
+// ttcFieldVersion23 is the first TTC field version (23c) that carry SQL
+// BOOLEAN. it is advertised to servers that support it
+const ttcFieldVersion23 = 17
+
+// ttcFieldVersionVector is the first TTC field version (23.4) that describe
+// dimensions and format of VECTOR columns
+const ttcFieldVersionVector = 24
+
+// nativeBoolean report if the negotiated TTC field version support SQL
+// BOOLEAN. older servers bind bool as NUMBER in SQL and PL/SQL BOOLEAN in
+// PL/SQL blocks
+func (conn *Connection) nativeBoolean() bool {
+	return conn.session != nil && conn.session.TTCVersion >= ttcFieldVersion23
+}
+
+// sessionLocation return time zone of the session used for TIMESTAMP WITH
+// LOCAL TIME ZONE values. the session time zone is set to the local offset
+// at logon and updated by the server when it is changed with ALTER SESSION
+func (conn *Connection) sessionLocation() *time.Location {
+	if conn.session == nil || len(conn.session.TimeZone) == 0 {
+		return time.Local
+	}
+	tz := strings.TrimSpace(strings.Trim(string(conn.session.TimeZone), "\x00"))
+	if len(tz) > 0 && (tz[0] == '+' || tz[0] == '-') {
+		var hours, minutes int
+		if _, err := fmt.Sscanf(tz[1:], "%d:%d", &hours, &minutes); err == nil {
+			offset := hours*3600 + minutes*60
+			if tz[0] == '-' {
+				offset = -offset
+			}
+			return time.FixedZone(tz, offset)
+		}
+	}
+	if loc, err := time.LoadLocation(tz); err == nil {
+		return loc
+	}
+	return time.Local
+}
+
 func (conn *Connection) doAuth() error {
 	conn.connOption.Tracer.Print("doAuth")
 	conn.session.ResetBuffer()
-	conn.session.PutBytes(3, 118, 0, 1)
+	conn.session.PutFunctionCode(3, 118)
+	conn.session.PutBytes(1)
 	conn.session.PutUint(len(conn.conStr.UserID), 4, true, true)
 	conn.LogonMode = conn.LogonMode | NoNewPass
 	conn.session.PutUint(int(conn.LogonMode), 4, true, true)
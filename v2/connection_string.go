@@ -1,12 +1,17 @@
 package go_ora
 
 import (
+	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net/url"
 	"path"
 	"strconv"
 	"strings"
+
+	"github.com/sijms/go-ora/v2/network"
+	"github.com/sijms/go-ora/v2/trace"
 )
 
 type PromotableTransaction int
@@ -54,6 +59,41 @@ func EnListFromString(s string) EnList {
 	}
 }
 
+// NumberFormat select the Go type of NUMBER column values
+type NumberFormat int
+
+const (
+	NumberAsNative  NumberFormat = 0 // int64 or float64
+	NumberAsDecimal NumberFormat = 1 // Decimal which keep all digits
+	NumberAsString  NumberFormat = 2 // decimal string which keep all digits
+)
+
+func NumberFormatFromString(s string) NumberFormat {
+	S := strings.ToUpper(s)
+	if S == "DECIMAL" {
+		return NumberAsDecimal
+	} else if S == "STRING" {
+		return NumberAsString
+	} else {
+		return NumberAsNative
+	}
+}
+
+// LobFetch select how BLOB and CLOB column values are returned
+type LobFetch int
+
+const (
+	LobFetchInline LobFetch = 0 // []byte and string read whole with the row
+	LobFetchStream LobFetch = 1 // *LobLocator read on demand
+)
+
+func LobFetchFromString(s string) LobFetch {
+	if strings.ToUpper(s) == "STREAM" {
+		return LobFetchStream
+	}
+	return LobFetchInline
+}
+
 type ConnectionString struct {
 	DataSource            string
 	Host                  string
@@ -91,8 +131,16 @@ type ConnectionString struct {
 	ConnectionPoolTimeout int
 	Trace                 string // Trace file
 	PrefetchRows          int
+	NumberFormat          NumberFormat
+	LobFetch              LobFetch
 	WalletPath            string
 	w                     *wallet
+	// the following options cannot be passed in the url they are set
+	// in code before calling NewConnector
+	TLSConfig   *tls.Config
+	Dialer      network.DialerContext
+	Tracer      trace.Tracer // used instead of Trace file when not nil
+	Credentials func(ctx context.Context) (userID, password string, err error)
 }
 
 func BuildUrl(server string, port int, service, user, password string, options map[string]string) string {
@@ -267,6 +315,10 @@ func newConnectionStringFromUrl(databaseUrl string) (*ConnectionString, error) {
 				ret.ProxyPassword = val[0]
 			case "TRACE FILE":
 				ret.Trace = val[0]
+			case "NUMBER FORMAT":
+				ret.NumberFormat = NumberFormatFromString(val[0])
+			case "LOB FETCH":
+				ret.LobFetch = LobFetchFromString(val[0])
 			case "PREFETCH_ROWS":
 				ret.PrefetchRows, err = strconv.Atoi(val[0])
 				if err != nil {
@@ -318,11 +370,13 @@ func (connStr *ConnectionString) validate() error {
 	//if connStr.SSL && (connStr.w == nil || len(connStr.w.certificates) == 0) {
 	//	return errors.New("tcps need a valid wallet contains server and client certificates")
 	//}
-	if len(connStr.UserID) == 0 {
-		return errors.New("empty user name")
-	}
-	if len(connStr.Password) == 0 {
-		return errors.New("empty password")
+	if connStr.Credentials == nil {
+		if len(connStr.UserID) == 0 {
+			return errors.New("empty user name")
+		}
+		if len(connStr.Password) == 0 {
+			return errors.New("empty password")
+		}
 	}
 	if len(connStr.SID) == 0 && len(connStr.ServiceName) == 0 {
 		return errors.New("empty SID and service name")
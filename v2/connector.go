@@ -0,0 +1,126 @@
+package go_ora
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync"
+)
+
+// Compile time Sentinels for implemented Interfaces.
+var _ = driver.Connector((*OracleConnector)(nil))
+var _ = driver.DriverContext((*OracleDriver)(nil))
+
+// OracleConnector open connections from a ConnectionString that is built
+// in code or parsed once from url. use it with sql.OpenDB
+type OracleConnector struct {
+	drv    *OracleDriver
+	conStr *ConnectionString
+	mu     sync.Mutex
+	pool   *Pool
+}
+
+// NewConnector return driver.Connector for the connection string. options
+// that cannot be passed in url (TLSConfig, Dialer, Tracer and Credentials)
+// are taken from conStr
+//
+//	conStr := go_ora.NewConnectionString()
+//	conStr.Host = "localhost"
+//	conStr.ServiceName = "orclpdb"
+//	conStr.UserID = "scott"
+//	conStr.Password = "tiger"
+//	connector, err := go_ora.NewConnector(conStr)
+//	// check for err
+//	db := sql.OpenDB(connector)
+func NewConnector(conStr *ConnectionString) (*OracleConnector, error) {
+	if len(conStr.Host) == 0 {
+		return nil, errors.New("empty host name")
+	}
+	// defaults and validation apply to a copy so the caller value is kept
+	temp := *conStr
+	if temp.Port == 0 {
+		temp.Port = 1521
+	}
+	err := temp.validate()
+	if err != nil {
+		return nil, err
+	}
+	return &OracleConnector{drv: &OracleDriver{}, conStr: &temp}, nil
+}
+
+func (connector *OracleConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	var conn *Connection
+	var err error
+	if connector.conStr.Pooling {
+		pool, err := connector.getPool(ctx)
+		if err != nil {
+			return nil, err
+		}
+		conn, err = pool.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		conn, err = openConnection(ctx, connector.conStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	connector.drv.setLastConn(conn)
+	return conn, nil
+}
+
+// getPool create the pool on first use so sql.Open and sql.OpenDB do not
+// connect to the server
+func (connector *OracleConnector) getPool(ctx context.Context) (*Pool, error) {
+	connector.mu.Lock()
+	defer connector.mu.Unlock()
+	if connector.pool == nil {
+		pool, err := newPool(ctx, connector.conStr)
+		if err != nil {
+			return nil, err
+		}
+		connector.pool = pool
+	}
+	return connector.pool, nil
+}
+
+// Close release the pool connections. it is called by sql.DB.Close
+func (connector *OracleConnector) Close() error {
+	connector.mu.Lock()
+	defer connector.mu.Unlock()
+	if connector.pool != nil {
+		err := connector.pool.Close()
+		connector.pool = nil
+		return err
+	}
+	return nil
+}
+
+func (connector *OracleConnector) Driver() driver.Driver {
+	return connector.drv
+}
+
+// openConnection open new connection from conStr. each connection get its
+// own copy of conStr so credentials returned from the callback are not
+// shared between connections
+func openConnection(ctx context.Context, conStr *ConnectionString) (*Connection, error) {
+	temp := *conStr
+	if temp.Credentials != nil {
+		var err error
+		temp.UserID, temp.Password, err = temp.Credentials(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	conn, err := newConnectionFromConnStr(&temp)
+	if err != nil {
+		return nil, err
+	}
+	err = conn.OpenWithContext(ctx)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
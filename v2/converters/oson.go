@@ -0,0 +1,720 @@
+package converters
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OSON is the binary format of JSON data type. the image start with a
+// header followed by field names segment and tree segment. objects refer to
+// field names by id and containers refer to their children by offset in
+// the tree segment
+
+const (
+	osonMagic1 = 0xFF
+	osonMagic2 = 0x4A
+	osonMagic3 = 0x5A
+
+	osonVersionMaxFName255   = 1
+	osonVersionMaxFName65535 = 3
+
+	// primary flags
+	osonFlagRelOffsetMode      = 0x01
+	osonFlagInlineLeaf         = 0x02
+	osonFlagNumFNamesUint32    = 0x08
+	osonFlagIsScalar           = 0x10
+	osonFlagHashIDUint8        = 0x0100
+	osonFlagNumFNamesUint16    = 0x0400
+	osonFlagFNamesSegUint32    = 0x0800
+	osonFlagTreeSegUint32      = 0x1000
+	osonFlagTinyNodesStat      = 0x2000
+	osonFlagSecFNamesSegUint16 = 0x0100 // secondary flags
+
+	// node types
+	osonNull          = 0x30
+	osonTrue          = 0x31
+	osonFalse         = 0x32
+	osonStringUint8   = 0x33
+	osonNumberUint8   = 0x34
+	osonBinaryDouble  = 0x36
+	osonStringUint16  = 0x37
+	osonStringUint32  = 0x38
+	osonTimeStamp     = 0x39
+	osonBinaryUint16  = 0x3A
+	osonBinaryUint32  = 0x3B
+	osonDate          = 0x3C
+	osonIntervalYM    = 0x3D
+	osonIntervalDS    = 0x3E
+	osonTimeStampTZ   = 0x7C
+	osonTimeStamp7    = 0x7D
+	osonID            = 0x7E
+	osonBinaryFloat   = 0x7F
+	osonObject        = 0x84
+	osonArray         = 0xC0
+	osonOffsetsUint32 = 0x20
+)
+
+type osonDecoder struct {
+	data           []byte
+	pos            int
+	treeSegPos     int
+	relative       bool
+	fieldIDSize    int
+	fieldNames     []string
+	namesOffsetLen int
+}
+
+// DecodeOSON convert OSON image into Go value. objects are returned as
+// map[string]interface{}, arrays as []interface{} and numbers as int64 or
+// float64 like NUMBER columns
+func DecodeOSON(data []byte) (interface{}, error) {
+	dec := &osonDecoder{data: data}
+	header, err := dec.read(4)
+	if err != nil {
+		return nil, err
+	}
+	if header[0] != osonMagic1 || header[1] != osonMagic2 || header[2] != osonMagic3 {
+		return nil, errors.New("invalid OSON image")
+	}
+	version := header[3]
+	if version != osonVersionMaxFName255 && version != osonVersionMaxFName65535 {
+		return nil, fmt.Errorf("unsupported OSON version: %d", version)
+	}
+	flags, err := dec.readUint(2)
+	if err != nil {
+		return nil, err
+	}
+	dec.relative = flags&osonFlagRelOffsetMode != 0
+	if flags&osonFlagIsScalar != 0 {
+		if flags&osonFlagTreeSegUint32 != 0 {
+			_, err = dec.read(4)
+		} else {
+			_, err = dec.read(2)
+		}
+		if err != nil {
+			return nil, err
+		}
+		dec.treeSegPos = dec.pos
+		return dec.decodeNode()
+	}
+	var numShortNames uint32
+	switch {
+	case flags&osonFlagNumFNamesUint32 != 0:
+		dec.fieldIDSize = 4
+	case flags&osonFlagNumFNamesUint16 != 0:
+		dec.fieldIDSize = 2
+	default:
+		dec.fieldIDSize = 1
+	}
+	numShortNames, err = dec.readUint(dec.fieldIDSize)
+	if err != nil {
+		return nil, err
+	}
+	var shortSegSize uint32
+	if flags&osonFlagFNamesSegUint32 != 0 {
+		dec.namesOffsetLen = 4
+	} else {
+		dec.namesOffsetLen = 2
+	}
+	shortSegSize, err = dec.readUint(dec.namesOffsetLen)
+	if err != nil {
+		return nil, err
+	}
+	var numLongNames, longSegSize uint32
+	longOffsetLen := 4
+	if version == osonVersionMaxFName65535 {
+		secFlags, err := dec.readUint(2)
+		if err != nil {
+			return nil, err
+		}
+		if secFlags&osonFlagSecFNamesSegUint16 != 0 {
+			longOffsetLen = 2
+		}
+		numLongNames, err = dec.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+		longSegSize, err = dec.readUint(4)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if flags&osonFlagTreeSegUint32 != 0 {
+		_, err = dec.read(4)
+	} else {
+		_, err = dec.read(2)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// number of tiny nodes
+	_, err = dec.read(2)
+	if err != nil {
+		return nil, err
+	}
+	err = dec.readFieldNames(int(numShortNames), int(shortSegSize), 1, dec.namesOffsetLen, 1)
+	if err != nil {
+		return nil, err
+	}
+	err = dec.readFieldNames(int(numLongNames), int(longSegSize), 2, longOffsetLen, 2)
+	if err != nil {
+		return nil, err
+	}
+	dec.treeSegPos = dec.pos
+	return dec.decodeNode()
+}
+
+func (dec *osonDecoder) read(size int) ([]byte, error) {
+	if size < 0 || dec.pos+size > len(dec.data) {
+		return nil, errors.New("unexpected end of OSON image")
+	}
+	ret := dec.data[dec.pos : dec.pos+size]
+	dec.pos += size
+	return ret, nil
+}
+
+func (dec *osonDecoder) readUint(size int) (uint32, error) {
+	data, err := dec.read(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint32(data[0]), nil
+	case 2:
+		return uint32(binary.BigEndian.Uint16(data)), nil
+	default:
+		return binary.BigEndian.Uint32(data), nil
+	}
+}
+
+// readFieldNames read hash ids, offsets and names of one field names
+// segment. the length of each name is stored before it in lenSize bytes
+func (dec *osonDecoder) readFieldNames(count, segSize, hashSize, offsetSize, lenSize int) error {
+	if count == 0 {
+		return nil
+	}
+	_, err := dec.read(count * hashSize)
+	if err != nil {
+		return err
+	}
+	offsets := make([]uint32, count)
+	for x := 0; x < count; x++ {
+		offsets[x], err = dec.readUint(offsetSize)
+		if err != nil {
+			return err
+		}
+	}
+	seg, err := dec.read(segSize)
+	if err != nil {
+		return err
+	}
+	for _, offset := range offsets {
+		start := int(offset) + lenSize
+		if start > len(seg) {
+			return errors.New("invalid OSON field name offset")
+		}
+		var nameLen int
+		if lenSize == 1 {
+			nameLen = int(seg[offset])
+		} else {
+			nameLen = int(binary.BigEndian.Uint16(seg[offset:]))
+		}
+		if start+nameLen > len(seg) {
+			return errors.New("invalid OSON field name length")
+		}
+		dec.fieldNames = append(dec.fieldNames, string(seg[start:start+nameLen]))
+	}
+	return nil
+}
+
+// osonFixedSize is the size of scalars with fixed length
+var osonFixedSize = map[uint32]int{
+	osonDate:         7,
+	osonTimeStamp7:   7,
+	osonTimeStamp:    11,
+	osonTimeStampTZ:  13,
+	osonBinaryFloat:  4,
+	osonBinaryDouble: 8,
+	osonIntervalDS:   11,
+	osonIntervalYM:   5,
+}
+
+func (dec *osonDecoder) decodeNode() (interface{}, error) {
+	nodeType, err := dec.readUint(1)
+	if err != nil {
+		return nil, err
+	}
+	if nodeType&0x80 != 0 {
+		return dec.decodeContainer(uint8(nodeType))
+	}
+	var size uint32
+	switch nodeType {
+	case osonNull:
+		return nil, nil
+	case osonTrue:
+		return true, nil
+	case osonFalse:
+		return false, nil
+	case osonDate, osonTimeStamp7, osonTimeStamp, osonTimeStampTZ, osonBinaryFloat, osonBinaryDouble,
+		osonIntervalDS, osonIntervalYM:
+		size = uint32(osonFixedSize[nodeType])
+	case osonStringUint8, osonNumberUint8, osonID:
+		size, err = dec.readUint(1)
+	case osonStringUint16, osonBinaryUint16:
+		size, err = dec.readUint(2)
+	case osonStringUint32, osonBinaryUint32:
+		size, err = dec.readUint(4)
+	default:
+		switch {
+		case nodeType&0xF0 == 0x20 || nodeType&0xF0 == 0x60:
+			// number with length in the node type
+			size = nodeType&0x0F + 1
+			nodeType = osonNumberUint8
+		case nodeType&0xF0 == 0x40 || nodeType&0xF0 == 0x50:
+			// integer with length in the node type
+			size = nodeType & 0x0F
+			nodeType = osonNumberUint8
+		case nodeType&0xE0 == 0:
+			// string with length in the node type
+			size = nodeType
+			nodeType = osonStringUint8
+		default:
+			return nil, fmt.Errorf("unsupported OSON node type: 0x%X", nodeType)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := dec.read(int(size))
+	if err != nil {
+		return nil, err
+	}
+	switch nodeType {
+	case osonNumberUint8:
+		return DecodeNumber(data), nil
+	case osonStringUint8, osonStringUint16, osonStringUint32:
+		return string(data), nil
+	case osonDate, osonTimeStamp7, osonTimeStamp:
+		return DecodeDate(data)
+	case osonTimeStampTZ:
+		return DecodeTimeStampTZ(data)
+	case osonBinaryFloat:
+		return DecodeBinaryFloat(data)
+	case osonBinaryDouble:
+		return DecodeBinaryDouble(data)
+	case osonIntervalDS:
+		return DecodeIntervalDS(data)
+	case osonIntervalYM:
+		return DecodeIntervalYM(data)
+	default:
+		ret := make([]byte, len(data))
+		copy(ret, data)
+		return ret, nil
+	}
+}
+
+func (dec *osonDecoder) decodeContainer(nodeType uint8) (interface{}, error) {
+	isObject := nodeType&0x40 == 0
+	containerOffset := dec.pos - dec.treeSegPos - 1
+	offsetSize := 2
+	if nodeType&osonOffsetsUint32 != 0 {
+		offsetSize = 4
+	}
+	var numChildren uint32
+	var err error
+	fieldIDsPos := 0
+	if nodeType&0x18 == 0x18 {
+		// object share field ids of another object
+		var sharedOffset uint32
+		sharedOffset, err = dec.readUint(offsetSize)
+		if err != nil {
+			return nil, err
+		}
+		if dec.relative {
+			sharedOffset += uint32(containerOffset)
+		}
+		pos := dec.pos
+		dec.pos = dec.treeSegPos + int(sharedOffset)
+		var sharedType uint32
+		sharedType, err = dec.readUint(1)
+		if err != nil {
+			return nil, err
+		}
+		numChildren, err = dec.readNumChildren(uint8(sharedType))
+		if err != nil {
+			return nil, err
+		}
+		fieldIDsPos = dec.pos
+		dec.pos = pos
+	} else {
+		numChildren, err = dec.readNumChildren(nodeType)
+		if err != nil {
+			return nil, err
+		}
+		if isObject {
+			fieldIDsPos = dec.pos
+			_, err = dec.read(int(numChildren) * dec.fieldIDSize)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	offsetsPos := dec.pos
+	var object map[string]interface{}
+	var array []interface{}
+	if isObject {
+		object = make(map[string]interface{}, numChildren)
+	} else {
+		array = make([]interface{}, 0, numChildren)
+	}
+	for x := 0; x < int(numChildren); x++ {
+		var name string
+		if isObject {
+			dec.pos = fieldIDsPos + x*dec.fieldIDSize
+			fieldID, err := dec.readUint(dec.fieldIDSize)
+			if err != nil {
+				return nil, err
+			}
+			if fieldID == 0 || int(fieldID) > len(dec.fieldNames) {
+				return nil, fmt.Errorf("invalid OSON field id: %d", fieldID)
+			}
+			name = dec.fieldNames[fieldID-1]
+		}
+		dec.pos = offsetsPos + x*offsetSize
+		offset, err := dec.readUint(offsetSize)
+		if err != nil {
+			return nil, err
+		}
+		if dec.relative {
+			offset += uint32(containerOffset)
+		}
+		dec.pos = dec.treeSegPos + int(offset)
+		child, err := dec.decodeNode()
+		if err != nil {
+			return nil, err
+		}
+		if isObject {
+			object[name] = child
+		} else {
+			array = append(array, child)
+		}
+	}
+	dec.pos = offsetsPos + int(numChildren)*offsetSize
+	if isObject {
+		return object, nil
+	}
+	return array, nil
+}
+
+func (dec *osonDecoder) readNumChildren(nodeType uint8) (uint32, error) {
+	switch nodeType & 0x18 {
+	case 0:
+		return dec.readUint(1)
+	case 0x08:
+		return dec.readUint(2)
+	default:
+		return dec.readUint(4)
+	}
+}
+
+type osonFieldName struct {
+	name   string
+	hashID uint32
+}
+
+type osonEncoder struct {
+	fieldIDs    map[string]int
+	fieldIDSize int
+	tree        []byte
+}
+
+// EncodeOSON convert Go value into OSON image. accepted values are nil,
+// bool, string, integers, float32, float64, json.Number, time.Time,
+// time.Duration, []byte, map[string]interface{} and []interface{}
+func EncodeOSON(value interface{}) ([]byte, error) {
+	enc := &osonEncoder{fieldIDs: map[string]int{}}
+	flags := osonFlagInlineLeaf
+	var names []osonFieldName
+	_, isObject := value.(map[string]interface{})
+	_, isArray := value.([]interface{})
+	if isObject || isArray {
+		err := collectFieldNames(value, enc.fieldIDs, &names)
+		if err != nil {
+			return nil, err
+		}
+		// field names are sorted by hash id, length and name and the field id
+		// is the position in the sorted list
+		sort.Slice(names, func(i, j int) bool {
+			if names[i].hashID&0xFF != names[j].hashID&0xFF {
+				return names[i].hashID&0xFF < names[j].hashID&0xFF
+			}
+			if len(names[i].name) != len(names[j].name) {
+				return len(names[i].name) < len(names[j].name)
+			}
+			return names[i].name < names[j].name
+		})
+		for x, item := range names {
+			enc.fieldIDs[item.name] = x + 1
+		}
+		flags |= osonFlagHashIDUint8 | osonFlagTinyNodesStat
+	} else {
+		flags |= osonFlagIsScalar
+	}
+	switch {
+	case len(names) < 0x100:
+		enc.fieldIDSize = 1
+	case len(names) < 0x10000:
+		enc.fieldIDSize = 2
+		flags |= osonFlagNumFNamesUint16
+	default:
+		enc.fieldIDSize = 4
+		flags |= osonFlagNumFNamesUint32
+	}
+	err := enc.encodeNode(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(enc.tree) > 0xFFFF {
+		flags |= osonFlagTreeSegUint32
+	}
+	var namesSeg []byte
+	for _, item := range names {
+		namesSeg = append(namesSeg, uint8(len(item.name)))
+		namesSeg = append(namesSeg, item.name...)
+	}
+	if len(namesSeg) > 0xFFFF {
+		flags |= osonFlagFNamesSegUint32
+	}
+	ret := []byte{osonMagic1, osonMagic2, osonMagic3, osonVersionMaxFName255}
+	ret = appendUint(ret, uint32(flags), 2)
+	if flags&osonFlagIsScalar == 0 {
+		ret = appendUint(ret, uint32(len(names)), enc.fieldIDSize)
+		offsetSize := 2
+		if flags&osonFlagFNamesSegUint32 != 0 {
+			offsetSize = 4
+		}
+		ret = appendUint(ret, uint32(len(namesSeg)), offsetSize)
+		if flags&osonFlagTreeSegUint32 != 0 {
+			ret = appendUint(ret, uint32(len(enc.tree)), 4)
+		} else {
+			ret = appendUint(ret, uint32(len(enc.tree)), 2)
+		}
+		// number of tiny nodes
+		ret = appendUint(ret, 0, 2)
+		for _, item := range names {
+			ret = append(ret, uint8(item.hashID))
+		}
+		offset := 0
+		for _, item := range names {
+			ret = appendUint(ret, uint32(offset), offsetSize)
+			offset += len(item.name) + 1
+		}
+		ret = append(ret, namesSeg...)
+	} else if flags&osonFlagTreeSegUint32 != 0 {
+		ret = appendUint(ret, uint32(len(enc.tree)), 4)
+	} else {
+		ret = appendUint(ret, uint32(len(enc.tree)), 2)
+	}
+	return append(ret, enc.tree...), nil
+}
+
+func appendUint(data []byte, val uint32, size int) []byte {
+	switch size {
+	case 1:
+		return append(data, uint8(val))
+	case 2:
+		return append(data, uint8(val>>8), uint8(val))
+	default:
+		return append(data, uint8(val>>24), uint8(val>>16), uint8(val>>8), uint8(val))
+	}
+}
+
+func putUint(data []byte, val uint32, size int) {
+	switch size {
+	case 1:
+		data[0] = uint8(val)
+	case 2:
+		binary.BigEndian.PutUint16(data, uint16(val))
+	default:
+		binary.BigEndian.PutUint32(data, val)
+	}
+}
+
+// collectFieldNames add names of all objects in value to names
+func collectFieldNames(value interface{}, seen map[string]int, names *[]osonFieldName) error {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if _, ok := seen[key]; !ok {
+				if len(key) > 0xFF {
+					return fmt.Errorf("OSON field name longer than 255 bytes: %s", key)
+				}
+				seen[key] = 0
+				// FNV-1a hash
+				hashID := uint32(0x811C9DC5)
+				for x := 0; x < len(key); x++ {
+					hashID = (hashID ^ uint32(key[x])) * 16777619
+				}
+				*names = append(*names, osonFieldName{name: key, hashID: hashID})
+			}
+			err := collectFieldNames(child, seen, names)
+			if err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range value {
+			err := collectFieldNames(child, seen, names)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (enc *osonEncoder) encodeContainer(nodeType uint8, numChildren int) {
+	nodeType |= osonOffsetsUint32
+	if numChildren > 0xFFFF {
+		nodeType |= 0x10
+	} else if numChildren > 0xFF {
+		nodeType |= 0x08
+	}
+	enc.tree = append(enc.tree, nodeType)
+	switch {
+	case numChildren > 0xFFFF:
+		enc.tree = appendUint(enc.tree, uint32(numChildren), 4)
+	case numChildren > 0xFF:
+		enc.tree = appendUint(enc.tree, uint32(numChildren), 2)
+	default:
+		enc.tree = appendUint(enc.tree, uint32(numChildren), 1)
+	}
+}
+
+func (enc *osonEncoder) encodeNumber(data []byte) {
+	enc.tree = append(enc.tree, osonNumberUint8, uint8(len(data)))
+	enc.tree = append(enc.tree, data...)
+}
+
+func (enc *osonEncoder) encodeNode(value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		enc.tree = append(enc.tree, osonNull)
+	case bool:
+		if value {
+			enc.tree = append(enc.tree, osonTrue)
+		} else {
+			enc.tree = append(enc.tree, osonFalse)
+		}
+	case int:
+		enc.encodeNumber(EncodeInt64(int64(value)))
+	case int8:
+		enc.encodeNumber(EncodeInt64(int64(value)))
+	case int16:
+		enc.encodeNumber(EncodeInt64(int64(value)))
+	case int32:
+		enc.encodeNumber(EncodeInt64(int64(value)))
+	case int64:
+		enc.encodeNumber(EncodeInt64(value))
+	case uint:
+		enc.encodeNumber(EncodeUint64(uint64(value)))
+	case uint8:
+		enc.encodeNumber(EncodeUint64(uint64(value)))
+	case uint16:
+		enc.encodeNumber(EncodeUint64(uint64(value)))
+	case uint32:
+		enc.encodeNumber(EncodeUint64(uint64(value)))
+	case uint64:
+		enc.encodeNumber(EncodeUint64(value))
+	case json.Number:
+		data, err := NumberFromString(string(value))
+		if err != nil {
+			return err
+		}
+		enc.encodeNumber(data)
+	case float32:
+		enc.tree = append(enc.tree, osonBinaryFloat)
+		enc.tree = append(enc.tree, EncodeBinaryFloat(value)...)
+	case float64:
+		enc.tree = append(enc.tree, osonBinaryDouble)
+		enc.tree = append(enc.tree, EncodeBinaryDouble(value)...)
+	case string:
+		switch {
+		case len(value) > 0xFFFF:
+			enc.tree = append(enc.tree, osonStringUint32)
+			enc.tree = appendUint(enc.tree, uint32(len(value)), 4)
+		case len(value) > 0xFF:
+			enc.tree = append(enc.tree, osonStringUint16)
+			enc.tree = appendUint(enc.tree, uint32(len(value)), 2)
+		default:
+			enc.tree = append(enc.tree, osonStringUint8, uint8(len(value)))
+		}
+		enc.tree = append(enc.tree, value...)
+	case []byte:
+		if len(value) > 0xFFFF {
+			enc.tree = append(enc.tree, osonBinaryUint32)
+			enc.tree = appendUint(enc.tree, uint32(len(value)), 4)
+		} else {
+			enc.tree = append(enc.tree, osonBinaryUint16)
+			enc.tree = appendUint(enc.tree, uint32(len(value)), 2)
+		}
+		enc.tree = append(enc.tree, value...)
+	case time.Time:
+		if value.Location() != time.UTC {
+			enc.tree = append(enc.tree, osonTimeStampTZ)
+			enc.tree = append(enc.tree, EncodeTimeStampTZ(value)...)
+		} else if value.Nanosecond() != 0 {
+			enc.tree = append(enc.tree, osonTimeStamp)
+			enc.tree = append(enc.tree, EncodeTimeStamp(value)...)
+		} else {
+			enc.tree = append(enc.tree, osonTimeStamp7)
+			enc.tree = append(enc.tree, EncodeDate(value)...)
+		}
+	case time.Duration:
+		enc.tree = append(enc.tree, osonIntervalDS)
+		enc.tree = append(enc.tree, EncodeIntervalDS(value)...)
+	case []interface{}:
+		enc.encodeContainer(osonArray, len(value))
+		offsetPos := len(enc.tree)
+		enc.tree = append(enc.tree, make([]byte, len(value)*4)...)
+		for _, child := range value {
+			putUint(enc.tree[offsetPos:], uint32(len(enc.tree)), 4)
+			offsetPos += 4
+			err := enc.encodeNode(child)
+			if err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		// children are written in order of field id
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return enc.fieldIDs[keys[i]] < enc.fieldIDs[keys[j]]
+		})
+		enc.encodeContainer(osonObject, len(value))
+		idPos := len(enc.tree)
+		offsetPos := idPos + len(value)*enc.fieldIDSize
+		enc.tree = append(enc.tree, make([]byte, len(value)*(enc.fieldIDSize+4))...)
+		for _, key := range keys {
+			putUint(enc.tree[idPos:], uint32(enc.fieldIDs[key]), enc.fieldIDSize)
+			putUint(enc.tree[offsetPos:], uint32(len(enc.tree)), 4)
+			idPos += enc.fieldIDSize
+			offsetPos += 4
+			err := enc.encodeNode(value[key])
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported JSON value type: %T", value)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package converters
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var zoneRegions = struct {
+	sync.RWMutex
+	names     map[int]string
+	locations map[int]*time.Location
+}{names: map[int]string{}, locations: map[int]*time.Location{}}
+
+// RegisterTimeZoneRegion map region id of oracle time zone file into time
+// zone name used to load location of TIMESTAMP WITH TIME ZONE values stored
+// with region name. values of regions that are not registered cannot be
+// decoded. empty name remove the region
+func RegisterTimeZoneRegion(id int, name string) {
+	zoneRegions.Lock()
+	defer zoneRegions.Unlock()
+	if len(name) == 0 {
+		delete(zoneRegions.names, id)
+	} else {
+		zoneRegions.names[id] = name
+	}
+	delete(zoneRegions.locations, id)
+}
+
+// regionLocation return location of region id. error is returned when the
+// region is not registered or its name is not found in the time zone database
+func regionLocation(id int) (*time.Location, error) {
+	zoneRegions.RLock()
+	loc, ok := zoneRegions.locations[id]
+	name, found := zoneRegions.names[id]
+	zoneRegions.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("time zone region id %d is not registered, use RegisterTimeZoneRegion to map it", id)
+	}
+	if ok {
+		return loc, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("time zone region id %d: %v", id, err)
+	}
+	zoneRegions.Lock()
+	zoneRegions.locations[id] = loc
+	zoneRegions.Unlock()
+	return loc, nil
+}
@@ -8,6 +8,7 @@ import (
 	"math"
 	"math/bits"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -24,6 +25,15 @@ func EncodeDate(ti time.Time) []byte {
 	return ret
 }
 
+// EncodeTimeStamp convert time.Time into TIMESTAMP representation with
+// nanoseconds. the wall clock of ti is stored without time zone
+func EncodeTimeStamp(ti time.Time) []byte {
+	ret := make([]byte, 11)
+	copy(ret, EncodeDate(ti))
+	binary.BigEndian.PutUint32(ret[7:11], uint32(ti.Nanosecond()))
+	return ret
+}
+
 // DecodeDate convert oracle time representation into time.Time
 func DecodeDate(data []byte) (time.Time, error) {
 	if len(data) < 7 {
@@ -57,6 +67,46 @@ func DecodeDate(data []byte) (time.Time, error) {
 	//	int(data[4]-1)+tzHour, int(data[5]-1)+tzMin, int(data[6]-1), nanoSec, time.UTC), nil
 }
 
+// EncodeTimeStampTZ convert time.Time into TIMESTAMP WITH TIME ZONE
+// representation. the value is stored in UTC with the offset of its location
+func EncodeTimeStampTZ(ti time.Time) []byte {
+	_, offset := ti.Zone()
+	ret := make([]byte, 13)
+	copy(ret, EncodeDate(ti.UTC()))
+	binary.BigEndian.PutUint32(ret[7:11], uint32(ti.Nanosecond()))
+	ret[11] = uint8(offset/3600 + 20)
+	ret[12] = uint8((offset/60)%60 + 60)
+	return ret
+}
+
+// DecodeTimeStampTZ convert TIMESTAMP WITH TIME ZONE representation into
+// time.Time with the stored offset. values stored with region name carry
+// region id that is loaded with time.LoadLocation when it is registered with
+// RegisterTimeZoneRegion. otherwise an error is returned
+func DecodeTimeStampTZ(data []byte) (time.Time, error) {
+	if len(data) < 13 {
+		return time.Time{}, errors.New("abnormal data representation for timestamp with time zone")
+	}
+	year := (int(data[0])-100)*100 + int(data[1]) - 100
+	ret := time.Date(year, time.Month(data[2]), int(data[3]), int(data[4])-1, int(data[5])-1,
+		int(data[6])-1, int(binary.BigEndian.Uint32(data[7:11])), time.UTC)
+	if data[11]&0x80 != 0 {
+		// region id: 7 bits in first byte and 6 bits in second byte
+		id := int(data[11]&0x7F)<<6 | int(data[12]&0xFC)>>2
+		loc, err := regionLocation(id)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return ret.In(loc), nil
+	}
+	tzHour := int(data[11]) - 20
+	tzMin := int(data[12]) - 60
+	if tzHour == 0 && tzMin == 0 {
+		return ret, nil
+	}
+	return ret.In(time.FixedZone("", tzHour*3600+tzMin*60)), nil
+}
+
 // addDigitToMantissa return the mantissa with the added digit if the carry is not
 // set by the add. Othervise, return the mantissa untouched and carry = true.
 func addDigitToMantissa(mantissaIn uint64, d byte) (mantissaOut uint64, carryOut bool) {
@@ -234,7 +284,9 @@ func ToNumber(mantissa []byte, negative bool, exponent int) []byte {
 
 	mantissaLen := len(mantissa)
 	size := 1 + (mantissaLen+1)/2
-	if negative && mantissaLen < 21 {
+	// negative number shorter than 20 mantissa bytes end with 0x66
+	terminated := negative && size < 21
+	if terminated {
 		size++
 	}
 	buf := make([]byte, size, size)
@@ -250,7 +302,7 @@ func ToNumber(mantissa []byte, negative bool, exponent int) []byte {
 		buf[1+i/2] = b + 1
 	}
 
-	if negative && mantissaLen < 21 {
+	if terminated {
 		buf[len(buf)-1] = 0x66
 	}
 
@@ -288,6 +340,14 @@ func EncodeInt(val int) []byte {
 	return EncodeInt64(int64(val))
 }
 
+// EncodeUint64 encode a uint64 into an oracle NUMBER internal format
+// Keep all digits of values above MaxInt64
+func EncodeUint64(val uint64) []byte {
+	mantissa := []byte(strconv.FormatUint(val, 10))
+	exponent := len(mantissa) - 1
+	return ToNumber(bytes.TrimRight(mantissa, "0"), false, exponent)
+}
+
 // EncodeDouble convert a float64 into binary NUMBER representation
 func EncodeDouble(num float64) ([]byte, error) {
 	if num == 0.0 {
@@ -315,3 +375,274 @@ func EncodeDouble(num float64) ([]byte, error) {
 	}
 	return ToNumber(mantissa, negative, exponent), nil
 }
+
+// NumberToString decode NUMBER into decimal text keeping all digits. the
+// result has no exponent and no trailing zeros after the decimal point
+func NumberToString(inputData []byte) (string, error) {
+	if len(inputData) == 0 {
+		return "", errors.New("invalid NUMBER")
+	}
+	if inputData[0] == 0x80 {
+		return "0", nil
+	}
+	negative := inputData[0]&0x80 == 0
+	var exponent int
+	buf := inputData[1:]
+	if negative {
+		exponent = int(inputData[0]^0x7f) - 65
+		if len(buf) > 0 && buf[len(buf)-1] == 0x66 {
+			buf = buf[:len(buf)-1]
+		}
+	} else {
+		exponent = int(inputData[0]&0x7f) - 65
+	}
+	if len(buf) == 0 {
+		return "", errors.New("invalid NUMBER")
+	}
+	digits := make([]byte, 0, len(buf)*2)
+	for _, digit100 := range buf {
+		if negative {
+			digit100 = 101 - digit100
+		} else {
+			digit100--
+		}
+		if digit100 > 99 {
+			return "", errors.New("invalid NUMBER")
+		}
+		digits = append(digits, '0'+digit100/10, '0'+digit100%10)
+	}
+	// each byte hold two digits so the point come after the digits of
+	// exponent+1 bytes
+	point := (exponent + 1) * 2
+	var intPart, fracPart []byte
+	switch {
+	case point <= 0:
+		fracPart = append(bytes.Repeat([]byte{'0'}, -point), digits...)
+	case point >= len(digits):
+		intPart = append(digits, bytes.Repeat([]byte{'0'}, point-len(digits))...)
+	default:
+		intPart, fracPart = digits[:point], digits[point:]
+	}
+	intPart = bytes.TrimLeft(intPart, "0")
+	fracPart = bytes.TrimRight(fracPart, "0")
+	var ret []byte
+	if negative {
+		ret = append(ret, '-')
+	}
+	if len(intPart) == 0 {
+		ret = append(ret, '0')
+	}
+	ret = append(ret, intPart...)
+	if len(fracPart) > 0 {
+		ret = append(ret, '.')
+		ret = append(ret, fracPart...)
+	}
+	return string(ret), nil
+}
+
+// NumberFromString encode decimal text like "-123.45" or "1.5e-3" into
+// NUMBER. return error when the text is out of NUMBER range or has more
+// significant digits than NUMBER can hold (38 to 40 depending on the
+// position of the decimal point)
+func NumberFromString(text string) ([]byte, error) {
+	input := strings.TrimSpace(text)
+	var err error
+	exponent := 0
+	if i := strings.IndexAny(input, "eE"); i >= 0 {
+		exponent, err = strconv.Atoi(input[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid number: %s", text)
+		}
+		input = input[:i]
+	}
+	negative := false
+	if len(input) > 0 && (input[0] == '-' || input[0] == '+') {
+		negative = input[0] == '-'
+		input = input[1:]
+	}
+	intLen := len(input)
+	if i := strings.IndexByte(input, '.'); i >= 0 {
+		intLen = i
+		input = input[:i] + input[i+1:]
+	}
+	if len(input) == 0 {
+		return nil, fmt.Errorf("invalid number: %s", text)
+	}
+	for _, ch := range input {
+		if ch < '0' || ch > '9' {
+			return nil, fmt.Errorf("invalid number: %s", text)
+		}
+	}
+	mantissa := strings.TrimLeft(input, "0")
+	intLen -= len(input) - len(mantissa)
+	mantissa = strings.TrimRight(mantissa, "0")
+	if len(mantissa) == 0 {
+		return []byte{128}, nil
+	}
+	exponent += intLen - 1
+	if exponent < -130 || exponent > 125 {
+		return nil, fmt.Errorf("number out of range: %s", text)
+	}
+	// digits are stored in pairs aligned to the decimal point
+	size := len(mantissa)
+	if exponent%2 == 0 {
+		size++
+	}
+	if size > 40 {
+		return nil, fmt.Errorf("number has too many significant digits: %s", text)
+	}
+	return ToNumber([]byte(mantissa), negative, exponent), nil
+}
+
+// EncodeBool convert bool into BOOLEAN representation
+func EncodeBool(val bool) []byte {
+	if val {
+		return []byte{1, 1}
+	}
+	return []byte{0}
+}
+
+// DecodeBool convert BOOLEAN into bool. the value is in the last byte
+func DecodeBool(data []byte) bool {
+	return len(data) > 0 && data[len(data)-1] == 1
+}
+
+// IntervalYM is value of INTERVAL YEAR TO MONTH. for negative interval
+// both Years and Months are negative or zero
+type IntervalYM struct {
+	Years  int
+	Months int
+}
+
+// intervalOffset is added to each field of oracle interval
+const (
+	intervalMid    = 0x80000000
+	intervalOffset = 60
+)
+
+// EncodeIntervalYM convert IntervalYM into INTERVAL YEAR TO MONTH
+// representation. months above 11 are carried into years
+func EncodeIntervalYM(val IntervalYM) []byte {
+	total := val.Years*12 + val.Months
+	ret := make([]byte, 5)
+	binary.BigEndian.PutUint32(ret, uint32(int64(total/12)+intervalMid))
+	ret[4] = uint8(total%12 + intervalOffset)
+	return ret
+}
+
+// DecodeIntervalYM convert INTERVAL YEAR TO MONTH representation into
+// IntervalYM
+func DecodeIntervalYM(data []byte) (IntervalYM, error) {
+	if len(data) < 5 {
+		return IntervalYM{}, errors.New("abnormal data representation for interval year to month")
+	}
+	return IntervalYM{
+		Years:  int(int64(binary.BigEndian.Uint32(data)) - intervalMid),
+		Months: int(data[4]) - intervalOffset,
+	}, nil
+}
+
+// EncodeIntervalDS convert time.Duration into INTERVAL DAY TO SECOND
+// representation
+func EncodeIntervalDS(val time.Duration) []byte {
+	day := 24 * time.Hour
+	ret := make([]byte, 11)
+	// division truncate toward zero so all fields have the sign of val
+	binary.BigEndian.PutUint32(ret, uint32(int64(val/day)+intervalMid))
+	val %= day
+	ret[4] = uint8(int(val/time.Hour) + intervalOffset)
+	val %= time.Hour
+	ret[5] = uint8(int(val/time.Minute) + intervalOffset)
+	val %= time.Minute
+	ret[6] = uint8(int(val/time.Second) + intervalOffset)
+	val %= time.Second
+	binary.BigEndian.PutUint32(ret[7:], uint32(int64(val)+intervalMid))
+	return ret
+}
+
+// DecodeIntervalDS convert INTERVAL DAY TO SECOND representation into
+// time.Duration. return error when the interval exceed time.Duration range
+// (about 106751 days)
+func DecodeIntervalDS(data []byte) (time.Duration, error) {
+	if len(data) < 11 {
+		return 0, errors.New("abnormal data representation for interval day to second")
+	}
+	days := int64(binary.BigEndian.Uint32(data)) - intervalMid
+	rest := time.Duration(int(data[4])-intervalOffset)*time.Hour +
+		time.Duration(int(data[5])-intervalOffset)*time.Minute +
+		time.Duration(int(data[6])-intervalOffset)*time.Second +
+		time.Duration(int64(binary.BigEndian.Uint32(data[7:]))-intervalMid)
+	maxDays := int64(math.MaxInt64 / int64(24*time.Hour))
+	if days > maxDays || days < -maxDays {
+		return 0, fmt.Errorf("interval of %d days is out of time.Duration range", days)
+	}
+	ret := time.Duration(days)*24*time.Hour + rest
+	if (days > 0 && rest > 0 && ret < 0) || (days < 0 && rest < 0 && ret > 0) {
+		return 0, fmt.Errorf("interval of %d days is out of time.Duration range", days)
+	}
+	return ret, nil
+}
+
+// EncodeBinaryFloat convert float32 into BINARY_FLOAT representation. the
+// sign bit of positive numbers is set and all bits of negative numbers are
+// inverted so the bytes sort like the numbers
+func EncodeBinaryFloat(num float32) []byte {
+	bits := math.Float32bits(num)
+	if num != num {
+		// canonical NaN
+		bits = 0x7FC00000
+	}
+	if bits&0x80000000 == 0 {
+		bits |= 0x80000000
+	} else {
+		bits = ^bits
+	}
+	ret := make([]byte, 4)
+	binary.BigEndian.PutUint32(ret, bits)
+	return ret
+}
+
+// DecodeBinaryFloat convert BINARY_FLOAT representation into float32
+func DecodeBinaryFloat(data []byte) (float32, error) {
+	if len(data) < 4 {
+		return 0, errors.New("abnormal data representation for binary float")
+	}
+	bits := binary.BigEndian.Uint32(data)
+	if bits&0x80000000 != 0 {
+		bits &= 0x7FFFFFFF
+	} else {
+		bits = ^bits
+	}
+	return math.Float32frombits(bits), nil
+}
+
+// EncodeBinaryDouble convert float64 into BINARY_DOUBLE representation
+func EncodeBinaryDouble(num float64) []byte {
+	bits := math.Float64bits(num)
+	if math.IsNaN(num) {
+		// canonical NaN
+		bits = 0x7FF8000000000000
+	}
+	if bits&0x8000000000000000 == 0 {
+		bits |= 0x8000000000000000
+	} else {
+		bits = ^bits
+	}
+	ret := make([]byte, 8)
+	binary.BigEndian.PutUint64(ret, bits)
+	return ret
+}
+
+// DecodeBinaryDouble convert BINARY_DOUBLE representation into float64
+func DecodeBinaryDouble(data []byte) (float64, error) {
+	if len(data) < 8 {
+		return 0, errors.New("abnormal data representation for binary double")
+	}
+	bits := binary.BigEndian.Uint64(data)
+	if bits&0x8000000000000000 != 0 {
+		bits &= 0x7FFFFFFFFFFFFFFF
+	} else {
+		bits = ^bits
+	}
+	return math.Float64frombits(bits), nil
+}
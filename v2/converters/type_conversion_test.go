@@ -3,7 +3,10 @@ package converters
 import (
 	"math"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Some documentation:
@@ -105,6 +108,25 @@ func TestEncodeInt(t *testing.T) {
 	}
 }
 
+func TestEncodeUint64(t *testing.T) {
+	for _, tt := range TestFloatValue {
+		if tt.IsInteger && tt.Integer >= 0 {
+			t.Run(tt.SelectText, func(t *testing.T) {
+				got := EncodeUint64(uint64(tt.Integer))
+				if !reflect.DeepEqual(got, tt.Binary) {
+					t.Errorf("EncodeUint64() = %v, want %v", got, tt.Binary)
+				}
+			})
+		}
+	}
+	got, err := NumberToString(EncodeUint64(math.MaxUint64))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	} else if got != "18446744073709551615" {
+		t.Errorf("NumberToString(EncodeUint64(MaxUint64)) = %s", got)
+	}
+}
+
 func TestEncodeDouble(t *testing.T) {
 
 	for _, tt := range TestFloatValue {
@@ -133,14 +155,470 @@ func TestEncodeDouble(t *testing.T) {
 	}
 }
 
-//
-// func TestEncodeDate(t *testing.T) {
-// 	ti := time.Date(2006, 01, 02, 15, 04, 06, 0, time.UTC)
+func TestEncodeBinaryDouble(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  float64
+		binary []byte
+	}{
+		{"zero", 0, []byte{0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{"one", 1, []byte{0xBF, 0xF0, 0, 0, 0, 0, 0, 0}},
+		{"minus one", -1, []byte{0x40, 0x0F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+		{"max", math.MaxFloat64, []byte{0xFF, 0xEF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+		{"smallest", math.SmallestNonzeroFloat64, []byte{0x80, 0, 0, 0, 0, 0, 0, 1}},
+		{"+inf", math.Inf(1), []byte{0xFF, 0xF0, 0, 0, 0, 0, 0, 0}},
+		{"-inf", math.Inf(-1), []byte{0x00, 0x0F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+		{"nan", math.NaN(), []byte{0xFF, 0xF8, 0, 0, 0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeBinaryDouble(tt.value)
+			if !reflect.DeepEqual(got, tt.binary) {
+				t.Errorf("EncodeBinaryDouble(%g) = %v, want %v", tt.value, got, tt.binary)
+			}
+			back, err := DecodeBinaryDouble(got)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if back != tt.value && !(math.IsNaN(back) && math.IsNaN(tt.value)) {
+				t.Errorf("DecodeBinaryDouble(EncodeBinaryDouble(%g)) = %g", tt.value, back)
+			}
+		})
+	}
+}
+
+func TestEncodeBinaryFloat(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  float32
+		binary []byte
+	}{
+		{"zero", 0, []byte{0x80, 0, 0, 0}},
+		{"one and half", 1.5, []byte{0xBF, 0xC0, 0, 0}},
+		{"minus one and half", -1.5, []byte{0x40, 0x3F, 0xFF, 0xFF}},
+		{"max", math.MaxFloat32, []byte{0xFF, 0x7F, 0xFF, 0xFF}},
+		{"+inf", float32(math.Inf(1)), []byte{0xFF, 0x80, 0, 0}},
+		{"-inf", float32(math.Inf(-1)), []byte{0x00, 0x7F, 0xFF, 0xFF}},
+		{"nan", float32(math.NaN()), []byte{0xFF, 0xC0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeBinaryFloat(tt.value)
+			if !reflect.DeepEqual(got, tt.binary) {
+				t.Errorf("EncodeBinaryFloat(%g) = %v, want %v", tt.value, got, tt.binary)
+			}
+			back, err := DecodeBinaryFloat(got)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if back != tt.value && !(back != back && tt.value != tt.value) {
+				t.Errorf("DecodeBinaryFloat(EncodeBinaryFloat(%g)) = %g", tt.value, back)
+			}
+		})
+	}
+}
+
+func TestEncodeTimeStamp(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  time.Time
+		binary []byte
+	}{
+		{"no fraction", time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+			[]byte{120, 121, 3, 4, 6, 7, 8, 0, 0, 0, 0}},
+		{"one nanosecond", time.Date(2021, 3, 4, 5, 6, 7, 1, time.UTC),
+			[]byte{120, 121, 3, 4, 6, 7, 8, 0, 0, 0, 1}},
+		{"max fraction", time.Date(2021, 3, 4, 5, 6, 7, 999999999, time.UTC),
+			[]byte{120, 121, 3, 4, 6, 7, 8, 0x3B, 0x9A, 0xC9, 0xFF}},
+		{"microseconds", time.Date(1999, 12, 31, 23, 59, 59, 123456000, time.UTC),
+			[]byte{119, 199, 12, 31, 24, 60, 60, 0x07, 0x5B, 0xCA, 0x00}},
+		{"first day", time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+			[]byte{100, 101, 1, 1, 1, 1, 1, 0, 0, 0, 0}},
+		{"last day", time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC),
+			[]byte{199, 199, 12, 31, 24, 60, 60, 0x3B, 0x9A, 0xC9, 0xFF}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeTimeStamp(tt.value)
+			if !reflect.DeepEqual(got, tt.binary) {
+				t.Errorf("EncodeTimeStamp(%v) = %v, want %v", tt.value, got, tt.binary)
+			}
+			back, err := DecodeDate(got)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if !back.Equal(tt.value) {
+				t.Errorf("DecodeDate(EncodeTimeStamp(%v)) = %v", tt.value, back)
+			}
+		})
+	}
+}
+
+func TestEncodeDate(t *testing.T) {
+	value := time.Date(2021, 3, 4, 5, 6, 7, 999999999, time.UTC)
+	got := EncodeDate(value)
+	want := []byte{120, 121, 3, 4, 6, 7, 8}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeDate(%v) = %v, want %v", value, got, want)
+	}
+	back, err := DecodeDate(got)
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+		return
+	}
+	if !back.Equal(value.Truncate(time.Second)) {
+		t.Errorf("DecodeDate(EncodeDate(%v)) = %v", value, back)
+	}
+}
+
+func TestEncodeTimeStampTZ(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  time.Time
+		binary []byte
+	}{
+		{"utc", time.Date(2021, 3, 4, 5, 6, 7, 8, time.UTC),
+			[]byte{120, 121, 3, 4, 6, 7, 8, 0, 0, 0, 8, 20, 60}},
+		{"positive offset", time.Date(2021, 3, 4, 1, 6, 7, 0, time.FixedZone("", 5*3600+30*60)),
+			[]byte{120, 121, 3, 3, 20, 37, 8, 0, 0, 0, 0, 25, 90}},
+		{"negative offset", time.Date(2021, 3, 4, 22, 6, 7, 0, time.FixedZone("", -(3*3600+30*60))),
+			[]byte{120, 121, 3, 5, 2, 37, 8, 0, 0, 0, 0, 17, 30}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeTimeStampTZ(tt.value)
+			if !reflect.DeepEqual(got, tt.binary) {
+				t.Errorf("EncodeTimeStampTZ(%v) = %v, want %v", tt.value, got, tt.binary)
+			}
+			back, err := DecodeTimeStampTZ(got)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			_, wantOffset := tt.value.Zone()
+			_, gotOffset := back.Zone()
+			if !back.Equal(tt.value) || gotOffset != wantOffset {
+				t.Errorf("DecodeTimeStampTZ(EncodeTimeStampTZ(%v)) = %v", tt.value, back)
+			}
+		})
+	}
+}
+
+func TestDecodeTimeStampTZRegion(t *testing.T) {
+	// 2021-03-04 05:06:07 UTC with region id 0x1FF (bytes 0x87 0xFC)
+	data := []byte{120, 121, 3, 4, 6, 7, 8, 0, 0, 0, 0, 0x87, 0xFC}
+	want := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	if got, err := DecodeTimeStampTZ(data); err == nil {
+		t.Errorf("unregistered region: expected error, got %v", got)
+	}
+	RegisterTimeZoneRegion(0x1FF, "Not/AZone")
+	if got, err := DecodeTimeStampTZ(data); err == nil {
+		t.Errorf("unknown region name: expected error, got %v", got)
+	}
+	RegisterTimeZoneRegion(0x1FF, "Europe/Paris")
+	defer RegisterTimeZoneRegion(0x1FF, "")
+	got, err := DecodeTimeStampTZ(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, offset := got.Zone(); !got.Equal(want) || name != "CET" || offset != 3600 {
+		t.Errorf("registered region: got %v, want %v in Europe/Paris", got, want)
+	}
+}
+
+func TestEncodeIntervalDS(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  time.Duration
+		binary []byte
+	}{
+		{"zero", 0, []byte{128, 0, 0, 0, 60, 60, 60, 128, 0, 0, 0}},
+		{"1 day 2:03:04.5", 26*time.Hour + 3*time.Minute + 4500*time.Millisecond,
+			[]byte{128, 0, 0, 1, 62, 63, 64, 0x9D, 0xCD, 0x65, 0}},
+		{"-1 second", -time.Second, []byte{128, 0, 0, 0, 60, 60, 59, 128, 0, 0, 0}},
+		{"-1 nanosecond", -1, []byte{128, 0, 0, 0, 60, 60, 60, 0x7F, 0xFF, 0xFF, 0xFF}},
+		{"-1 day 2:03:04.5", -(26*time.Hour + 3*time.Minute + 4500*time.Millisecond),
+			[]byte{0x7F, 0xFF, 0xFF, 0xFF, 58, 57, 56, 0x62, 0x32, 0x9B, 0}},
+		{"max", math.MaxInt64, nil},
+		{"min", math.MinInt64, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeIntervalDS(tt.value)
+			if tt.binary != nil && !reflect.DeepEqual(got, tt.binary) {
+				t.Errorf("EncodeIntervalDS(%v) = %v, want %v", tt.value, got, tt.binary)
+			}
+			back, err := DecodeIntervalDS(got)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if back != tt.value {
+				t.Errorf("DecodeIntervalDS(EncodeIntervalDS(%v)) = %v", tt.value, back)
+			}
+		})
+	}
+}
+
+func TestDecodeIntervalDSOutOfRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		binary []byte
+	}{
+		{"too many days", []byte{128, 1, 0xA1, 0x00, 60, 60, 60, 128, 0, 0, 0}},
+		{"max days and one more second", []byte{128, 1, 0xA0, 0xFF, 83, 107, 77, 0xB2, 0xF2, 0xD7, 0xFF}},
+		{"short data", []byte{128, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := DecodeIntervalDS(tt.binary); err == nil {
+				t.Errorf("DecodeIntervalDS(%v) = %v, want error", tt.binary, got)
+			}
+		})
+	}
+}
+
+func TestEncodeIntervalYM(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  IntervalYM
+		want   IntervalYM
+		binary []byte
+	}{
+		{"zero", IntervalYM{}, IntervalYM{}, []byte{128, 0, 0, 0, 60}},
+		{"1 year 2 months", IntervalYM{1, 2}, IntervalYM{1, 2}, []byte{128, 0, 0, 1, 62}},
+		{"-1 year -2 months", IntervalYM{-1, -2}, IntervalYM{-1, -2}, []byte{0x7F, 0xFF, 0xFF, 0xFF, 58}},
+		{"-1 month", IntervalYM{0, -1}, IntervalYM{0, -1}, []byte{128, 0, 0, 0, 59}},
+		{"14 months", IntervalYM{0, 14}, IntervalYM{1, 2}, []byte{128, 0, 0, 1, 62}},
+		{"max", IntervalYM{999999999, 11}, IntervalYM{999999999, 11}, []byte{0xBB, 0x9A, 0xC9, 0xFF, 71}},
+		{"min", IntervalYM{-999999999, -11}, IntervalYM{-999999999, -11}, []byte{0x44, 0x65, 0x36, 0x01, 49}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EncodeIntervalYM(tt.value)
+			if !reflect.DeepEqual(got, tt.binary) {
+				t.Errorf("EncodeIntervalYM(%v) = %v, want %v", tt.value, got, tt.binary)
+			}
+			back, err := DecodeIntervalYM(got)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if back != tt.want {
+				t.Errorf("DecodeIntervalYM(EncodeIntervalYM(%v)) = %v, want %v", tt.value, back, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumberToString(t *testing.T) {
+	for _, tt := range TestFloatValue {
+		t.Run(tt.SelectText, func(t *testing.T) {
+			got, err := NumberToString(tt.Binary)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			f, err := strconv.ParseFloat(got, 64)
+			if err != nil {
+				t.Errorf("NumberToString(%v) = %s is not a number", tt.Binary, got)
+				return
+			}
+			if tt.Float != 0.0 {
+				e := math.Abs((f - tt.Float) / tt.Float)
+				if e > 1e-15 {
+					t.Errorf("NumberToString(%v) = %s, want %g, Error= %e", tt.Binary, got, tt.Float, e)
+				}
+			}
+			data, err := NumberFromString(got)
+			if err != nil {
+				t.Errorf("Unexpected error: %s", err)
+				return
+			}
+			if !reflect.DeepEqual(data, tt.Binary) {
+				t.Errorf("NumberFromString(%s) = %v, want %v", got, data, tt.Binary)
+			}
+		})
+	}
+}
+
+func TestNumberStringPrecision(t *testing.T) {
+	tests := []string{
+		"12345678901234567890123456789012345678",
+		"-12345678901234567890123456789012345678",
+		"1234567890123456789012345678901234567890",
+		"-0.0000000001234567890123456789012345678901",
+		"9999999999999999999.9999999999999999999",
+	}
+	for _, tt := range tests {
+		data, err := NumberFromString(tt)
+		if err != nil {
+			t.Errorf("NumberFromString(%s) unexpected error: %s", tt, err)
+			continue
+		}
+		got, err := NumberToString(data)
+		if err != nil {
+			t.Errorf("NumberToString(%v) unexpected error: %s", data, err)
+			continue
+		}
+		if got != tt {
+			t.Errorf("NumberToString(NumberFromString(%s)) = %s", tt, got)
+		}
+	}
+	for _, tt := range []string{"1.2.3", "12a", "", "1e126", "12345678901234567890123456789012345678901"} {
+		if _, err := NumberFromString(tt); err == nil {
+			t.Errorf("NumberFromString(%q) expected error", tt)
+		}
+	}
+}
+
+func TestEncodeBool(t *testing.T) {
+	for _, val := range []bool{true, false} {
+		got := DecodeBool(EncodeBool(val))
+		if got != val {
+			t.Errorf("DecodeBool(EncodeBool(%v)) = %v", val, got)
+		}
+	}
+	if DecodeBool(nil) {
+		t.Errorf("DecodeBool(nil) = true")
+	}
+}
 
-// 	got := EncodeDate(ti)
-// 	want := []byte{214, 7, 1, 2, 15, 4, 5, 0}
+func TestEncodeOSON(t *testing.T) {
+	got, err := EncodeOSON("a")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	want := []byte{0xFF, 0x4A, 0x5A, 1, 0, 0x12, 0, 3, 0x33, 1, 'a'}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeOSON(\"a\") = %v, want %v", got, want)
+	}
+	value := map[string]interface{}{
+		"id":      int64(10),
+		"name":    "first",
+		"price":   12.5,
+		"active":  true,
+		"deleted": nil,
+		"tags":    []interface{}{"a", strings.Repeat("b", 300), int64(-3)},
+		"child":   map[string]interface{}{"id": int64(1), "data": []byte{1, 2, 3}},
+		"created": time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC),
+	}
+	data, err := EncodeOSON(value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	decoded, err := DecodeOSON(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(decoded, value) {
+		t.Errorf("DecodeOSON(EncodeOSON(%v)) = %v", value, decoded)
+	}
+	if _, err = EncodeOSON(map[string]interface{}{"a": struct{}{}}); err == nil {
+		t.Errorf("EncodeOSON expected error for unsupported value")
+	}
+}
 
-// 	if !reflect.DeepEqual(got, want) {
-// 		t.Errorf("EncodeDate(%v) = %v, want %v", ti, got, want)
-// 	}
-// }
+func TestDecodeOSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want interface{}
+	}{
+		{"scalar string", []byte{0xFF, 0x4A, 0x5A, 1, 0, 0x12, 0, 3, 0x33, 1, 'a'}, "a"},
+		// {"a":1,"b":"xy"} with 2 bytes offsets, number and string length in
+		// the node type
+		{"object", []byte{0xFF, 0x4A, 0x5A, 1, 0x21, 0, 2, 0, 4, 0, 14, 0, 0,
+			0x2C, 0xE5, 0, 0, 0, 2, 1, 'a', 1, 'b',
+			0x84, 2, 1, 2, 0, 8, 0, 11, 0x21, 0xC1, 2, 2, 'x', 'y'},
+			map[string]interface{}{"a": int64(1), "b": "xy"}},
+		// [{"a":1,"b":"xy"},true] with offsets relative to the container
+		{"relative offsets", []byte{0xFF, 0x4A, 0x5A, 1, 0x21, 1, 2, 0, 4, 0, 21, 0, 0,
+			0x2C, 0xE5, 0, 0, 0, 2, 1, 'a', 1, 'b',
+			0xC0, 2, 0, 6, 0, 20,
+			0x84, 2, 1, 2, 0, 8, 0, 11, 0x21, 0xC1, 2, 2, 'x', 'y', 0x31},
+			[]interface{}{map[string]interface{}{"a": int64(1), "b": "xy"}, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeOSON(tt.data)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeOSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := DecodeOSON([]byte{0xFF, 0x4A, 0x5A, 1, 0x21, 0, 2}); err == nil {
+		t.Errorf("DecodeOSON expected error for truncated image")
+	}
+}
+
+func TestEncodeVector(t *testing.T) {
+	got, err := EncodeVector([]int8{1, -1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	want := []byte{0xDB, 0, 0, 0x12, 4, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0xFF}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeVector([]int8{1, -1}) = %v, want %v", got, want)
+	}
+	values := []interface{}{
+		[]float32{1.5, -2.25, 0, 3e10},
+		[]float64{1.5, -2.25, 0, 3e100},
+		[]int8{127, -128, 0},
+	}
+	for _, value := range values {
+		data, err := EncodeVector(value)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		decoded, err := DecodeVector(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(decoded, value) {
+			t.Errorf("DecodeVector(EncodeVector(%v)) = %v", value, decoded)
+		}
+	}
+	binaryVector := []byte{0xDB, 0, 0, 0, 5, 0, 0, 0, 16, 0xAA, 0x55}
+	decoded, err := DecodeVector(binaryVector)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(decoded, []uint8{0xAA, 0x55}) {
+		t.Errorf("DecodeVector(binary) = %v", decoded)
+	}
+	if _, err = EncodeVector([]int{1}); err == nil {
+		t.Errorf("EncodeVector expected error for unsupported value")
+	}
+}
+
+func TestEncodeXMLImage(t *testing.T) {
+	text := []byte("<a>1</a>")
+	data := EncodeXMLImage(text)
+	flag, payload, err := DecodeXMLImage(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if flag != XMLImageString || !reflect.DeepEqual(payload, text) {
+		t.Errorf("DecodeXMLImage(EncodeXMLImage(%s)) = %d, %s", text, flag, payload)
+	}
+	// image with prefix segment that hold CLOB locator
+	locator := []byte{0, 0x70, 0, 1, 1, 0x0C}
+	data = append([]byte{0x81, 1, 0, 1, 1, 1, 0, 0, 0, 1}, locator...)
+	flag, payload, err = DecodeXMLImage(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if flag != XMLImageLob || !reflect.DeepEqual(payload, locator) {
+		t.Errorf("DecodeXMLImage(%v) = %d, %v", data, flag, payload)
+	}
+	if _, _, err = DecodeXMLImage([]byte{0x85, 1, 0x0C, 1, 0, 0, 0, 0x10}); err == nil {
+		t.Errorf("DecodeXMLImage expected error for binary XML")
+	}
+}
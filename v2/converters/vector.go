@@ -0,0 +1,139 @@
+package converters
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// VectorFormat is the storage format of VECTOR elements
+type VectorFormat uint8
+
+const (
+	VectorFloat32 VectorFormat = 2
+	VectorFloat64 VectorFormat = 3
+	VectorInt8    VectorFormat = 4
+	VectorBinary  VectorFormat = 5
+)
+
+const (
+	vectorMagic         = 0xDB
+	vectorVersionBase   = 0
+	vectorVersionSparse = 2
+	vectorFlagNorm      = 0x0002
+	vectorFlagNormRes   = 0x0010
+	vectorFlagSparse    = 0x0020
+)
+
+func (format VectorFormat) String() string {
+	switch format {
+	case VectorFloat32:
+		return "FLOAT32"
+	case VectorFloat64:
+		return "FLOAT64"
+	case VectorInt8:
+		return "INT8"
+	case VectorBinary:
+		return "BINARY"
+	default:
+		return fmt.Sprintf("VectorFormat(%d)", uint8(format))
+	}
+}
+
+// EncodeVector convert []float32, []float64 or []int8 into VECTOR image.
+// the element type select the storage format
+func EncodeVector(values interface{}) ([]byte, error) {
+	var format VectorFormat
+	var count int
+	switch values := values.(type) {
+	case []float32:
+		format, count = VectorFloat32, len(values)
+	case []float64:
+		format, count = VectorFloat64, len(values)
+	case []int8:
+		format, count = VectorInt8, len(values)
+	default:
+		return nil, fmt.Errorf("unsupported VECTOR value type: %T", values)
+	}
+	ret := make([]byte, 17, 17+count*8)
+	ret[0] = vectorMagic
+	ret[1] = vectorVersionBase
+	// norm is calculated by the server
+	binary.BigEndian.PutUint16(ret[2:], vectorFlagNorm|vectorFlagNormRes)
+	ret[4] = uint8(format)
+	binary.BigEndian.PutUint32(ret[5:], uint32(count))
+	switch values := values.(type) {
+	case []float32:
+		for _, val := range values {
+			ret = append(ret, EncodeBinaryFloat(val)...)
+		}
+	case []float64:
+		for _, val := range values {
+			ret = append(ret, EncodeBinaryDouble(val)...)
+		}
+	case []int8:
+		for _, val := range values {
+			ret = append(ret, uint8(val))
+		}
+	}
+	return ret, nil
+}
+
+// DecodeVector convert VECTOR image into []float32, []float64, []int8 or
+// []uint8 (packed bits of BINARY format) according to the storage format
+func DecodeVector(data []byte) (interface{}, error) {
+	if len(data) < 9 || data[0] != vectorMagic {
+		return nil, errors.New("invalid VECTOR image")
+	}
+	if data[1] > vectorVersionSparse {
+		return nil, fmt.Errorf("unsupported VECTOR version: %d", data[1])
+	}
+	flags := binary.BigEndian.Uint16(data[2:])
+	if flags&vectorFlagSparse != 0 {
+		return nil, errors.New("sparse VECTOR is not supported")
+	}
+	format := VectorFormat(data[4])
+	count := int(binary.BigEndian.Uint32(data[5:]))
+	data = data[9:]
+	if flags&(vectorFlagNorm|vectorFlagNormRes) != 0 {
+		if len(data) < 8 {
+			return nil, errors.New("invalid VECTOR image")
+		}
+		data = data[8:]
+	}
+	size := map[VectorFormat]int{VectorFloat32: 4, VectorFloat64: 8, VectorInt8: 1}[format]
+	if format == VectorBinary {
+		// each element is one bit
+		count, size = count/8, 1
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("unsupported VECTOR format: %d", format)
+	}
+	if len(data) < count*size {
+		return nil, errors.New("invalid VECTOR image")
+	}
+	switch format {
+	case VectorFloat32:
+		ret := make([]float32, count)
+		for x := range ret {
+			ret[x], _ = DecodeBinaryFloat(data[x*4 : x*4+4])
+		}
+		return ret, nil
+	case VectorFloat64:
+		ret := make([]float64, count)
+		for x := range ret {
+			ret[x], _ = DecodeBinaryDouble(data[x*8 : x*8+8])
+		}
+		return ret, nil
+	case VectorInt8:
+		ret := make([]int8, count)
+		for x := range ret {
+			ret[x] = int8(data[x])
+		}
+		return ret, nil
+	default:
+		ret := make([]uint8, count)
+		copy(ret, data)
+		return ret, nil
+	}
+}
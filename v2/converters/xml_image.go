@@ -0,0 +1,91 @@
+package converters
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// flags of XMLTYPE pickled image
+const (
+	XMLImageLob    uint32 = 0x1
+	XMLImageString uint32 = 0x4
+	xmlImageSkip4  uint32 = 0x100000
+)
+
+const (
+	objImageVersion81   = 0x80
+	objImageNoPrefixSeg = 0x04
+	objImageTopLevel    = 0x01
+	objLongLength       = 0xFE
+)
+
+// DecodeXMLImage read pickled image of XMLTYPE and return XMLImageString
+// with the document text or XMLImageLob with CLOB locator that hold the
+// document
+func DecodeXMLImage(data []byte) (uint32, []byte, error) {
+	invalid := errors.New("invalid XMLTYPE image")
+	if len(data) < 3 {
+		return 0, nil, invalid
+	}
+	flags := data[0]
+	index := 2
+	// image length
+	if data[index] == objLongLength {
+		index += 5
+	} else {
+		index++
+	}
+	if flags&objImageNoPrefixSeg == 0 {
+		if index >= len(data) {
+			return 0, nil, invalid
+		}
+		size := int(data[index])
+		index++
+		if size == objLongLength {
+			if index+4 > len(data) {
+				return 0, nil, invalid
+			}
+			size = int(binary.BigEndian.Uint32(data[index:]))
+			index += 4
+		}
+		index += size
+	}
+	// xml version
+	index++
+	if index+4 > len(data) {
+		return 0, nil, invalid
+	}
+	xmlFlag := binary.BigEndian.Uint32(data[index:])
+	index += 4
+	if xmlFlag&xmlImageSkip4 != 0 {
+		index += 4
+	}
+	if index > len(data) {
+		return 0, nil, invalid
+	}
+	switch {
+	case xmlFlag&XMLImageString != 0:
+		return XMLImageString, data[index:], nil
+	case xmlFlag&XMLImageLob != 0:
+		return XMLImageLob, data[index:], nil
+	default:
+		// binary XML is sent only to clients that negotiate client side
+		// decoding of XMLTYPE
+		return 0, nil, fmt.Errorf("unsupported XMLTYPE image flag: %#x (binary XML storage)", xmlFlag)
+	}
+}
+
+// EncodeXMLImage create pickled image of XMLTYPE that hold the document
+// text
+func EncodeXMLImage(text []byte) []byte {
+	ret := make([]byte, 12, 12+len(text))
+	ret[0] = objImageVersion81 | objImageNoPrefixSeg | objImageTopLevel
+	ret[1] = 1
+	ret[2] = objLongLength
+	ret[7] = 1
+	binary.BigEndian.PutUint32(ret[8:], XMLImageString)
+	ret = append(ret, text...)
+	binary.BigEndian.PutUint32(ret[3:], uint32(len(ret)))
+	return ret
+}
@@ -1,6 +1,7 @@
 package go_ora
 
 import (
+	"context"
 	"database/sql/driver"
 	"github.com/sijms/go-ora/v2/trace"
 	"io"
@@ -16,7 +17,7 @@ var _ = driver.RowsColumnTypeNullable((*DataSet)(nil))
 
 // var _ = driver.RowsColumnTypePrecisionScale((*DataSet)(nil))
 // var _ = driver.RowsColumnTypeScanType((*DataSet)(nil))
-// var _ = driver.RowsNextResultSet((*DataSet)(nil))
+var _ = driver.RowsNextResultSet((*DataSet)(nil))
 
 type Row []driver.Value
 
@@ -30,6 +31,13 @@ type DataSet struct {
 	//currentRow      Row
 	index  int
 	parent StmtInterface
+	// implicit result being fetched and the ones that follow
+	cursor  *RefCursor
+	results []*RefCursor
+	// cursors from select list of fetched rows
+	nested []*RefCursor
+	// ctx of QueryContext that interrupt fetch round trips
+	ctx context.Context
 }
 
 func (dataSet *DataSet) load(session *network.Session) error {
@@ -90,7 +98,112 @@ func (dataSet *DataSet) setBitVector(bitVector []byte) {
 
 }
 
+// Close close nested and implicit result cursors on the server
 func (dataSet *DataSet) Close() error {
+	err := dataSet.closeNested()
+	if dataSet.cursor != nil {
+		if temp := dataSet.cursor.Close(); temp != nil && err == nil {
+			err = temp
+		}
+		dataSet.cursor = nil
+	}
+	for _, cursor := range dataSet.results {
+		if temp := cursor.Close(); temp != nil && err == nil {
+			err = temp
+		}
+	}
+	dataSet.results = nil
+	return err
+}
+
+// closeNested close cursors of select list that are not closed by the user
+func (dataSet *DataSet) closeNested() error {
+	var conn *Connection
+	cursorIDs := make([]int, 0, len(dataSet.nested))
+	for _, cursor := range dataSet.nested {
+		if cursor.dataSet != nil {
+			_ = cursor.dataSet.closeNested()
+		}
+		if cursor.cursorID != 0 {
+			cursorIDs = append(cursorIDs, cursor.cursorID)
+			cursor.cursorID = 0
+			conn = cursor.connection
+		}
+	}
+	dataSet.nested = nil
+	if len(cursorIDs) == 0 {
+		return nil
+	}
+	return closeCursors(conn, cursorIDs)
+}
+
+// releaseNested close cursors of select list in rows before row. cursors
+// that the user started to fetch are kept until they or the parent are
+// closed
+func (dataSet *DataSet) releaseNested(row int) error {
+	var conn *Connection
+	var cursorIDs []int
+	kept := dataSet.nested[:0]
+	for _, cursor := range dataSet.nested {
+		if cursor.row >= row || cursor.dataSet != nil {
+			kept = append(kept, cursor)
+			continue
+		}
+		if cursor.cursorID != 0 {
+			cursorIDs = append(cursorIDs, cursor.cursorID)
+			cursor.cursorID = 0
+			conn = cursor.connection
+		}
+	}
+	for x := len(kept); x < len(dataSet.nested); x++ {
+		dataSet.nested[x] = nil
+	}
+	dataSet.nested = kept
+	if len(cursorIDs) == 0 {
+		return nil
+	}
+	return closeCursors(conn, cursorIDs)
+}
+
+func (dataSet *DataSet) HasNextResultSet() bool {
+	return len(dataSet.results) > 0
+}
+
+// NextResultSet close the current implicit result and move to the next one
+func (dataSet *DataSet) NextResultSet() error {
+	if len(dataSet.results) == 0 {
+		return io.EOF
+	}
+	if dataSet.cursor != nil {
+		err := dataSet.cursor.Close()
+		dataSet.cursor = nil
+		if err != nil {
+			return err
+		}
+	}
+	return dataSet.nextResult()
+}
+
+// nextResult replace content of the data set with rows of the next
+// implicit result
+func (dataSet *DataSet) nextResult() error {
+	cursor := dataSet.results[0]
+	results := dataSet.results[1:]
+	// cursors of the previous result are lost when the content is replaced
+	if err := dataSet.closeNested(); err != nil {
+		dataSet.results = results
+		_ = cursor.Close()
+		return err
+	}
+	temp, err := cursor.Query()
+	if err != nil {
+		dataSet.results = results
+		_ = cursor.Close()
+		return err
+	}
+	*dataSet = *temp
+	dataSet.cursor = cursor
+	dataSet.results = results
 	return nil
 }
 
@@ -116,6 +229,17 @@ func (dataSet *DataSet) Next(dest []driver.Value) error {
 	if !hasMoreRows && noOfRowsToFetch == 0 {
 		return io.EOF
 	}
+	if len(dataSet.nested) > 0 && dataSet.index > 0 {
+		// cursors of the rows already returned. all rows are done when the
+		// next batch is fetched
+		current := dataSet.index % len(dataSet.Rows)
+		if current == 0 {
+			current = len(dataSet.Rows)
+		}
+		if err := dataSet.releaseNested(current); err != nil {
+			return err
+		}
+	}
 	if dataSet.index > 0 && dataSet.index%len(dataSet.Rows) == 0 {
 		if hasMoreRows {
 			dataSet.Rows = make([]Row, 0, dataSet.parent.noOfRowsToFetch())
@@ -199,6 +323,8 @@ func (dataSet DataSet) ColumnTypeLength(index int) (length int64, ok bool) {
 		return int64(dataSet.Cols[index].MaxCharLen), true
 	case NUMBER:
 		return int64(dataSet.Cols[index].Precision), true
+	case VECTOR:
+		return int64(dataSet.Cols[index].VectorDimensions), true
 	}
 	return int64(0), false
 
@@ -0,0 +1,44 @@
+package go_ora
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReleaseNested(t *testing.T) {
+	started := &RefCursor{row: 0, dataSet: &DataSet{}}
+	idle := &RefCursor{row: 0}
+	current := &RefCursor{row: 1}
+	next := &RefCursor{row: 2}
+	dataSet := &DataSet{nested: []*RefCursor{started, idle, current, next}}
+	if err := dataSet.releaseNested(1); err != nil {
+		t.Fatal(err)
+	}
+	want := []*RefCursor{started, current, next}
+	if len(dataSet.nested) != len(want) {
+		t.Fatalf("expected %d nested cursors, got %d", len(want), len(dataSet.nested))
+	}
+	for x := range want {
+		if dataSet.nested[x] != want[x] {
+			t.Errorf("nested cursor %d: expected row %d, got row %d", x, want[x].row, dataSet.nested[x].row)
+		}
+	}
+	if err := dataSet.releaseNested(3); err != nil {
+		t.Fatal(err)
+	}
+	if len(dataSet.nested) != 1 || dataSet.nested[0] != started {
+		t.Errorf("expected only the started cursor to be kept, got %d cursors", len(dataSet.nested))
+	}
+}
+
+func TestNextCanceledFetch(t *testing.T) {
+	stmt := &defaultStmt{connection: newTestConnection(), _hasMoreRows: true, _noOfRowsToFetch: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	dataSet := &DataSet{parent: stmt, Rows: []Row{{}}, index: 1, ctx: ctx}
+	cancel()
+	err := dataSet.Next(nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled from next fetch, got %v", err)
+	}
+}
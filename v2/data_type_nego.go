@@ -66,6 +66,20 @@ func buildTypeNego(nego *TCPNego, session *network.Session) *DataTypeNego {
 	if len(result.Server.ServerCompileTimeCaps) <= 27 || result.Server.ServerCompileTimeCaps[27] == 0 {
 		result.CompileTimeCaps[27] = 0
 	}
+	if len(result.Server.ServerCompileTimeCaps) > 7 {
+		// 23c field version carry SQL BOOLEAN and 23.4 describe VECTOR
+		// columns. older servers keep the 18c field version
+		switch serverVersion := result.Server.ServerCompileTimeCaps[7]; {
+		case serverVersion >= ttcFieldVersionVector:
+			result.CompileTimeCaps[7] = ttcFieldVersionVector
+			// TTC5 vector support and binary vector feature
+			result.CompileTimeCaps = append(result.CompileTimeCaps, make([]byte, 12)...)
+			result.CompileTimeCaps[44] = 8
+			result.CompileTimeCaps[52] = 1
+		case serverVersion >= ttcFieldVersion23:
+			result.CompileTimeCaps[7] = ttcFieldVersion23
+		}
+	}
 	xmlTypeClientSideDecoding := false
 	if len(result.Server.ServerCompileTimeCaps) > 7 {
 		if result.Server.ServerCompileTimeCaps[7] >= 8 && xmlTypeClientSideDecoding {
@@ -357,10 +371,11 @@ func buildTypeNego(nego *TCPNego, session *network.Session) *DataTypeNego {
 	result.addTypeRep(115, 115, 1)
 	result.addTypeRep(116, 102, 1)
 	result.addTypeRep(118, 0, 0)
-	result.addTypeRep(119, 0, 0)
+	result.addTypeRep(119, 119, 1)
 	result.addTypeRep(121, 0, 0)
 	result.addTypeRep(122, 0, 0)
 	result.addTypeRep(123, 0, 0)
+	result.addTypeRep(127, 127, 1)
 	result.addTypeRep(136, 0, 0)
 	result.addTypeRep(146, 146, 1)
 	result.addTypeRep(147, 0, 0)
@@ -803,6 +818,16 @@ func (nego *DataTypeNego) read(session *network.Session) error {
 
 	return nil
 }
+// fieldVersion return TTC field version used by the session. it is the
+// smaller of client and server compile time capability
+func (nego *DataTypeNego) fieldVersion() uint8 {
+	version := nego.CompileTimeCaps[7]
+	if len(nego.Server.ServerCompileTimeCaps) > 7 && nego.Server.ServerCompileTimeCaps[7] < version {
+		version = nego.Server.ServerCompileTimeCaps[7]
+	}
+	return version
+}
+
 func (nego *DataTypeNego) write(session *network.Session) error {
 	session.ResetBuffer()
 	if nego.Server.ServerCompileTimeCaps == nil || len(nego.Server.ServerCompileTimeCaps) <= 27 || nego.Server.ServerCompileTimeCaps[27] == 0 {
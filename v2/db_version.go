@@ -19,7 +19,8 @@ type DBVersion struct {
 
 func GetDBVersion(session *network.Session) (*DBVersion, error) {
 	session.ResetBuffer()
-	session.PutBytes(3, 0x3B, 0, 1)
+	session.PutFunctionCode(3, 0x3B)
+	session.PutBytes(1)
 	//session.PutUint(1, 1, false, false)
 	session.PutUint(0x100, 2, true, true)
 	session.PutBytes(1, 1)
@@ -0,0 +1,113 @@
+package go_ora
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/sijms/go-ora/v2/converters"
+)
+
+// Decimal is exact value of NUMBER. NUMBER hold up to 40 significant digits
+// which lose precision when returned as float64. set NUMBER FORMAT option
+// to DECIMAL to receive NUMBER columns as Decimal
+//
+//	var total go_ora.Decimal
+//	err := db.QueryRow("SELECT SUM(AMOUNT) FROM T1").Scan(&total)
+//	// check for err
+//	rat := total.Rat()
+//
+// Decimal, *big.Int and *big.Float are bound as NUMBER
+type Decimal struct {
+	// text is decimal representation without exponent. empty text is 0
+	text string
+}
+
+// NewDecimal create Decimal from decimal text like "-123.45" or "1.5e-3"
+func NewDecimal(text string) (Decimal, error) {
+	data, err := converters.NumberFromString(text)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return newDecimalFromBytes(data)
+}
+
+// NewDecimalFromBigInt create Decimal from *big.Int. return error when the
+// value has more than 40 digits
+func NewDecimalFromBigInt(val *big.Int) (Decimal, error) {
+	if val == nil {
+		return Decimal{}, errors.New("cannot create Decimal from nil *big.Int")
+	}
+	return NewDecimal(val.String())
+}
+
+func newDecimalFromBytes(data []byte) (Decimal, error) {
+	text, err := converters.NumberToString(data)
+	if err != nil {
+		return Decimal{}, err
+	}
+	return Decimal{text: text}, nil
+}
+
+func (dec Decimal) String() string {
+	if len(dec.text) == 0 {
+		return "0"
+	}
+	return dec.text
+}
+
+// Rat return the value as *big.Rat
+func (dec Decimal) Rat() *big.Rat {
+	ret, _ := new(big.Rat).SetString(dec.String())
+	return ret
+}
+
+// Int return the value as *big.Int. return error when the value has
+// fraction
+func (dec Decimal) Int() (*big.Int, error) {
+	rat := dec.Rat()
+	if !rat.IsInt() {
+		return nil, fmt.Errorf("decimal %s is not an integer", dec.String())
+	}
+	return new(big.Int).Set(rat.Num()), nil
+}
+
+// Int64 return the value as int64. return error when the value has
+// fraction or out of int64 range
+func (dec Decimal) Int64() (int64, error) {
+	return strconv.ParseInt(dec.String(), 10, 64)
+}
+
+// Float64 return the nearest float64 to the value
+func (dec Decimal) Float64() float64 {
+	ret, _ := strconv.ParseFloat(dec.String(), 64)
+	return ret
+}
+
+func (dec Decimal) encode() ([]byte, error) {
+	return converters.NumberFromString(dec.String())
+}
+
+// Scan implement sql.Scanner. NULL cannot be scanned into Decimal use
+// *Decimal instead
+func (dec *Decimal) Scan(value interface{}) error {
+	var err error
+	switch val := value.(type) {
+	case Decimal:
+		*dec = val
+	case string:
+		*dec, err = NewDecimal(val)
+	case []byte:
+		*dec, err = NewDecimal(string(val))
+	case int64:
+		*dec = Decimal{text: strconv.FormatInt(val, 10)}
+	case float64:
+		*dec, err = NewDecimal(strconv.FormatFloat(val, 'e', -1, 64))
+	case nil:
+		return errors.New("cannot scan NULL into Decimal")
+	default:
+		return fmt.Errorf("cannot scan value of type %T into Decimal", value)
+	}
+	return err
+}
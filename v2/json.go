@@ -0,0 +1,74 @@
+package go_ora
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/sijms/go-ora/v2/converters"
+)
+
+// JSONValue bind any value that encoding/json can marshal (structs, slices,
+// maps) as JSON. slices are used for array binding and structs are not
+// driver values so JSON parameters of these types should be wrapped in
+// JSONValue
+//
+//	_, err = db.Exec("INSERT INTO ORDERS(ID, DOC) VALUES(:1, :2)", id, go_ora.JSONValue{Value: order})
+//
+// when scanned, Value receive the decoded JSON value. if Value hold a
+// pointer the JSON value is unmarshaled into it
+type JSONValue struct {
+	Value interface{}
+}
+
+func (val JSONValue) encode() ([]byte, error) {
+	if val.Value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(val.Value)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSONText(data)
+}
+
+// Scan implement sql.Scanner
+func (val *JSONValue) Scan(value interface{}) error {
+	if temp, ok := value.(JSONValue); ok {
+		*val = temp
+		return nil
+	}
+	if val.Value != nil && reflect.TypeOf(val.Value).Kind() == reflect.Ptr {
+		if value == nil {
+			return nil
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(data, val.Value)
+		if err != nil {
+			return fmt.Errorf("cannot scan JSON value into %T: %v", val.Value, err)
+		}
+		return nil
+	}
+	val.Value = value
+	return nil
+}
+
+// encodeJSONText convert JSON text into OSON image
+func encodeJSONText(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var temp interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// keep numbers exact
+	decoder.UseNumber()
+	err := decoder.Decode(&temp)
+	if err != nil {
+		return nil, err
+	}
+	return converters.EncodeOSON(temp)
+}
@@ -0,0 +1,65 @@
+package go_ora
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sijms/go-ora/v2/converters"
+)
+
+func TestJSONValueParam(t *testing.T) {
+	type order struct {
+		ID   int      `json:"id"`
+		Tags []string `json:"tags"`
+	}
+	stmt := NewStmt("INSERT INTO T1 VALUES(:1)", newTestConnection())
+	tests := []struct {
+		value interface{}
+		want  interface{}
+	}{
+		{order{ID: 1, Tags: []string{"a"}}, map[string]interface{}{"id": int64(1), "tags": []interface{}{"a"}}},
+		{[]interface{}{"a", true}, []interface{}{"a", true}},
+	}
+	for _, test := range tests {
+		par, err := stmt.newParam("", JSONValue{Value: test.value}, 0, Input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if par.DataType != JSON {
+			t.Errorf("JSONValue{%v} bound as %v", test.value, par.DataType)
+		}
+		got, err := converters.DecodeOSON(par.BValue)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("JSONValue{%v} encoded as %v", test.value, got)
+		}
+	}
+	if _, err := stmt.newParam("", JSONValue{Value: make(chan int)}, 0, Input); err == nil {
+		t.Errorf("expected error for value that cannot be marshaled")
+	}
+}
+
+func TestJSONValueScan(t *testing.T) {
+	type order struct {
+		ID   int      `json:"id"`
+		Tags []string `json:"tags"`
+	}
+	value := map[string]interface{}{"id": int64(7), "tags": []interface{}{"a", "b"}}
+	var dest order
+	doc := JSONValue{Value: &dest}
+	if err := doc.Scan(value); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if dest.ID != 7 || !reflect.DeepEqual(dest.Tags, []string{"a", "b"}) {
+		t.Errorf("Scan into struct = %+v", dest)
+	}
+	var generic JSONValue
+	if err := generic.Scan(value); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(generic.Value, value) {
+		t.Errorf("Scan = %v, want %v", generic.Value, value)
+	}
+}
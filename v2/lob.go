@@ -2,11 +2,23 @@ package go_ora
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"github.com/sijms/go-ora/v2/network"
 )
 
+// lob operations
+const (
+	lobOpGetLength  = 0x1
+	lobOpRead       = 0x2
+	lobOpTrim       = 0x20
+	lobOpWrite      = 0x40
+	lobOpCreateTemp = 0x110
+	lobOpFreeTemp   = 0x111
+	lobOpChunkSize  = 0x4000
+)
+
 type Lob struct {
 	sourceLocator []byte
 	destLocator   []byte
@@ -27,6 +39,34 @@ func (lob *Lob) variableWidthChar() bool {
 func (lob *Lob) littleEndianClob() bool {
 	return len(lob.sourceLocator) > 7 && lob.sourceLocator[7]&64 > 0
 }
+
+// decodeString decode CLOB data according to the locator charset
+func (lob *Lob) decodeString(connection *Connection, data []byte, charsetID int) string {
+	tempCharset := connection.strConv.GetLangID()
+	if lob.variableWidthChar() {
+		if connection.dBVersion.Number < 10200 && lob.littleEndianClob() {
+			connection.strConv.SetLangID(2002)
+		} else {
+			connection.strConv.SetLangID(2000)
+		}
+	} else {
+		connection.strConv.SetLangID(charsetID)
+	}
+	defer connection.strConv.SetLangID(tempCharset)
+	return connection.strConv.Decode(data)
+}
+
+// encodeString encode CLOB data according to the locator charset
+func (lob *Lob) encodeString(connection *Connection, text string, charsetID int) []byte {
+	tempCharset := connection.strConv.GetLangID()
+	if lob.variableWidthChar() {
+		connection.strConv.SetLangID(2000)
+	} else {
+		connection.strConv.SetLangID(charsetID)
+	}
+	defer connection.strConv.SetLangID(tempCharset)
+	return connection.strConv.Encode(text)
+}
 func (lob *Lob) getSize(connection *Connection) (size int64, err error) {
 	session := connection.session
 	connection.connOption.Tracer.Print("Read Lob Size")
@@ -59,7 +99,7 @@ func (lob *Lob) getData(connection *Connection) (data []byte, err error) {
 }
 func (lob *Lob) write(session *network.Session, operationID int) error {
 	session.ResetBuffer()
-	session.PutBytes(3, 0x60, 0)
+	session.PutFunctionCode(3, 0x60)
 	if len(lob.sourceLocator) == 0 {
 		session.PutBytes(0)
 	} else {
@@ -94,10 +134,12 @@ func (lob *Lob) write(session *network.Session, operationID int) error {
 		session.PutBytes(0)
 	}
 
-	// if bNullO2U (false) {
-	// session.PutBytes(1)
-	//} else {
-	session.PutBytes(0)
+	// bNullO2U
+	if operationID == lobOpCreateTemp {
+		session.PutBytes(1)
+	} else {
+		session.PutBytes(0)
+	}
 
 	session.PutInt(operationID, 4, true, true)
 	if len(lob.scn) == 0 {
@@ -134,6 +176,10 @@ func (lob *Lob) write(session *network.Session, operationID int) error {
 	for x := 0; x < len(lob.scn); x++ {
 		session.PutUint(lob.scn[x], 4, true, true)
 	}
+	if operationID == lobOpWrite {
+		session.PutBytes(14)
+		session.PutClr(lob.data.Bytes())
+	}
 	if session.TTCVersion >= 3 {
 		session.PutUint(lob.size, 8, true, true)
 	}
@@ -165,10 +211,12 @@ func (lob *Lob) read(connection *Connection) error {
 		case 8:
 			// read rpa message
 			if len(lob.sourceLocator) != 0 {
-				_, err = session.GetBytes(len(lob.sourceLocator))
+				// the server return updated locator
+				locator, err := session.GetBytes(len(lob.sourceLocator))
 				if err != nil {
 					return err
 				}
+				copy(lob.sourceLocator, locator)
 			}
 			if len(lob.destLocator) != 0 {
 				_, err = session.GetBytes(len(lob.destLocator))
@@ -287,3 +335,16 @@ func (lob *Lob) readData(session *network.Session) error {
 	}
 	return nil
 }
+
+// valueLocator return locator of LOB value sent inline with the bind data
+// like OSON image of JSON parameter
+func valueLocator(size int) []byte {
+	ret := make([]byte, 40)
+	binary.BigEndian.PutUint16(ret, 38)    // length of the locator after this field
+	binary.BigEndian.PutUint16(ret[2:], 4) // locator version
+	ret[4] = 0x61                          // value based, abstract BLOB
+	ret[5] = 8                             // initialized
+	binary.BigEndian.PutUint16(ret[8:], 1)
+	binary.BigEndian.PutUint64(ret[10:], uint64(size))
+	return ret
+}
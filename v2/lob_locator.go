@@ -0,0 +1,498 @@
+package go_ora
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// LobLocator give on demand access to BLOB and CLOB values. set LOB FETCH
+// option to STREAM to receive LOB columns as *LobLocator instead of []byte
+// and string which are read whole into memory
+//
+//	conn, err := db.Conn(ctx)
+//	// check for err
+//	defer conn.Close()
+//	var doc *go_ora.LobLocator
+//	err = conn.QueryRowContext(ctx, "SELECT DATA FROM DOCS WHERE ID = :1", id).Scan(&doc)
+//	// check for err
+//	_, err = io.Copy(file, doc)
+//
+// data is read in chunks of the LOB optimal chunk size. offsets of BLOB are
+// in bytes and offsets of CLOB are in characters while Read of CLOB return
+// UTF-8 text. only select list columns are streamed and output parameters
+// are still read whole. LobLocator is bound as BLOB or CLOB. the locator use
+// the connection so use sql.Conn to keep the connection while the locator
+// is used and don't use it while another call is in progress. the locator
+// fail once the connection is returned to the pool or closed
+type LobLocator struct {
+	connection *Connection
+	// lease of the connection when the locator is created
+	lease     int32
+	lob       Lob
+	isClob    bool
+	charsetID int
+	chunkSize int64
+	size      int64
+	// pos is 0 based offset of the data after pending
+	pos     int64
+	pending []byte
+	// partial hold bytes of incomplete character of CLOB Write
+	partial   []byte
+	temporary bool
+}
+
+func newLobLocator(connection *Connection, locator []byte, isClob bool, charsetID int) *LobLocator {
+	return &LobLocator{
+		connection: connection,
+		lease:      connectionLease(connection),
+		lob:        Lob{sourceLocator: locator},
+		isClob:     isClob,
+		charsetID:  charsetID,
+		size:       -1,
+	}
+}
+
+func connectionLease(connection *Connection) int32 {
+	if connection == nil {
+		return 0
+	}
+	return atomic.LoadInt32(&connection.lobLease)
+}
+
+// checkLease return error when the connection is released after the locator
+// is created
+func (loc *LobLocator) checkLease() error {
+	if loc.connection == nil || loc.connection.session == nil || connectionLease(loc.connection) != loc.lease {
+		return errors.New("LOB locator is used after its connection is released")
+	}
+	return nil
+}
+
+// IsClob return true for CLOB and NCLOB locators
+func (loc *LobLocator) IsClob() bool {
+	return loc.isClob
+}
+
+// Size return length of the LOB in bytes for BLOB and characters for CLOB
+func (loc *LobLocator) Size() (int64, error) {
+	if loc.size >= 0 {
+		return loc.size, nil
+	}
+	if err := loc.checkLease(); err != nil {
+		return 0, err
+	}
+	err := loc.operation(lobOpGetLength)
+	if err != nil {
+		return 0, err
+	}
+	loc.size = loc.lob.size
+	return loc.size, nil
+}
+
+// ChunkSize return optimal amount of data for read and write operations
+func (loc *LobLocator) ChunkSize() (int64, error) {
+	if loc.chunkSize > 0 {
+		return loc.chunkSize, nil
+	}
+	if err := loc.checkLease(); err != nil {
+		return 0, err
+	}
+	err := loc.operation(lobOpChunkSize)
+	if err != nil {
+		return 0, err
+	}
+	loc.chunkSize = loc.lob.size
+	if loc.chunkSize <= 0 {
+		loc.chunkSize = 0x8000
+	}
+	return loc.chunkSize, nil
+}
+
+// operation send lob operation that return its result in size
+func (loc *LobLocator) operation(operationID int) error {
+	loc.lob.size = 0
+	loc.lob.sourceOffset = 0
+	loc.lob.data.Reset()
+	err := loc.lob.write(loc.connection.session, operationID)
+	if err != nil {
+		return err
+	}
+	return loc.lob.read(loc.connection)
+}
+
+// readAt read amount units of data starting from 0 based offset and return
+// the data with number of units read
+func (loc *LobLocator) readAt(offset, amount int64) ([]byte, int64, error) {
+	if err := loc.checkLease(); err != nil {
+		return nil, 0, err
+	}
+	loc.connection.connOption.Tracer.Printf("Read Lob: offset=%d amount=%d", offset, amount)
+	loc.lob.data.Reset()
+	loc.lob.sourceOffset = int(offset) + 1
+	loc.lob.size = amount
+	err := loc.lob.write(loc.connection.session, lobOpRead)
+	if err != nil {
+		return nil, 0, err
+	}
+	err = loc.lob.read(loc.connection)
+	if err != nil {
+		return nil, 0, err
+	}
+	data := loc.lob.data.Bytes()
+	if loc.isClob {
+		data = []byte(loc.lob.decodeString(loc.connection, data, loc.charsetID))
+	} else {
+		data = append([]byte(nil), data...)
+	}
+	return data, loc.lob.size, nil
+}
+
+// Read implement io.Reader
+func (loc *LobLocator) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if len(loc.pending) == 0 {
+		chunkSize, err := loc.ChunkSize()
+		if err != nil {
+			return 0, err
+		}
+		amount := chunkSize
+		if count := int64(len(p)) / chunkSize; count > 1 {
+			amount = count * chunkSize
+		}
+		data, count, err := loc.readAt(loc.pos, amount)
+		if err != nil {
+			return 0, err
+		}
+		if count == 0 || len(data) == 0 {
+			return 0, io.EOF
+		}
+		loc.pos += count
+		loc.pending = data
+	}
+	n := copy(p, loc.pending)
+	loc.pending = loc.pending[n:]
+	return n, nil
+}
+
+// ReaderAt return io.ReaderAt of BLOB. CLOB offsets are in characters which
+// cannot be mapped to bytes of UTF-8 text so use Seek and Read for CLOB
+func (loc *LobLocator) ReaderAt() (io.ReaderAt, error) {
+	if loc.isClob {
+		return nil, errors.New("io.ReaderAt is supported only for BLOB")
+	}
+	return blobReaderAt{loc}, nil
+}
+
+// blobReaderAt read BLOB at byte offsets
+type blobReaderAt struct {
+	loc *LobLocator
+}
+
+// ReadAt implement io.ReaderAt
+func (reader blobReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative LOB offset")
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	data, _, err := reader.loc.readAt(off, int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	n := copy(p, data)
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// Seek implement io.Seeker. offset is in characters for CLOB
+func (loc *LobLocator) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += loc.pos - loc.pendingSize()
+	case io.SeekEnd:
+		size, err := loc.Size()
+		if err != nil {
+			return 0, err
+		}
+		offset += size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative LOB offset")
+	}
+	loc.pos = offset
+	loc.pending = nil
+	return offset, nil
+}
+
+// pendingSize return number of units in data read but not returned
+func (loc *LobLocator) pendingSize() int64 {
+	if loc.isClob {
+		return clobLength(loc.pending, loc.lob.variableWidthChar())
+	}
+	return int64(len(loc.pending))
+}
+
+// clobLength return length of UTF-8 text in CLOB characters. variable width
+// CLOB is stored in UTF-16 so characters outside BMP take 2 characters
+func clobLength(text []byte, variableWidth bool) int64 {
+	var length int64
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		text = text[size:]
+		length++
+		if variableWidth && r > 0xFFFF {
+			length++
+		}
+	}
+	return length
+}
+
+// NewTempLob create temporary BLOB or CLOB that live until Free is called
+// or the session end. data is written with Write, WriteAt and Append
+//
+//	lob, err := go_ora.NewTempLob(conn, false)
+//	// check for err
+//	defer lob.Free()
+//	_, err = io.Copy(lob, file)
+//	// check for err
+//	_, err = conn.Exec("INSERT INTO DOCS(ID, DATA) VALUES(:1, :2)", id, lob)
+func NewTempLob(connection *Connection, isClob bool) (*LobLocator, error) {
+	charsetID := 0
+	if isClob {
+		charsetID = connection.tcpNego.ServerCharset
+	}
+	loc := newLobLocator(connection, make([]byte, 40), isClob, charsetID)
+	loc.temporary = true
+	lob := &loc.lob
+	lob.data.Reset()
+	// charset form and type
+	if isClob {
+		lob.sourceOffset = 1
+		lob.destOffset = int(OCIClobLocator)
+	} else {
+		lob.destOffset = int(OCIBlobLocator)
+	}
+	lob.charsetID = 873
+	// session duration
+	lob.size = 10
+	connection.connOption.Tracer.Print("Create Temporary Lob")
+	err := lob.write(connection.session, lobOpCreateTemp)
+	if err == nil {
+		err = lob.read(connection)
+	}
+	lob.charsetID = 0
+	lob.destOffset = 0
+	if err != nil {
+		return nil, err
+	}
+	loc.size = 0
+	return loc, nil
+}
+
+// writeAt write data at 0 based offset. amount is the length of data in
+// units of the LOB
+func (loc *LobLocator) writeAt(data []byte, offset, amount int64) error {
+	if err := loc.checkLease(); err != nil {
+		return err
+	}
+	loc.connection.connOption.Tracer.Printf("Write Lob: offset=%d amount=%d", offset, amount)
+	loc.lob.data.Reset()
+	loc.lob.data.Write(data)
+	loc.lob.sourceOffset = int(offset) + 1
+	loc.lob.size = amount
+	err := loc.lob.write(loc.connection.session, lobOpWrite)
+	loc.lob.data.Reset()
+	if err != nil {
+		return err
+	}
+	loc.size = -1
+	loc.pending = nil
+	return loc.lob.read(loc.connection)
+}
+
+// WriteAt implement io.WriterAt. off is in characters for CLOB and p
+// should hold complete UTF-8 characters
+func (loc *LobLocator) WriteAt(p []byte, off int64) (int, error) {
+	written, _, err := loc.write(p, off)
+	return written, err
+}
+
+// write data at 0 based offset and return number of bytes of p written and
+// number of LOB units (characters of CLOB) written
+func (loc *LobLocator) write(p []byte, off int64) (int, int64, error) {
+	if off < 0 {
+		return 0, 0, errors.New("negative LOB offset")
+	}
+	chunkSize, err := loc.ChunkSize()
+	if err != nil {
+		return 0, 0, err
+	}
+	// send at most about 1MB in one call
+	pieceSize := int(chunkSize)
+	if count := 0x100000 / pieceSize; count > 1 {
+		pieceSize *= count
+	}
+	written := 0
+	var units int64
+	for written < len(p) {
+		end := written + pieceSize
+		if end > len(p) {
+			end = len(p)
+		}
+		piece := p[written:end]
+		amount := int64(len(piece))
+		if loc.isClob {
+			// don't split character
+			end = written + runeBoundary(p[written:], end-written)
+			piece = p[written:end]
+			text := string(piece)
+			piece = loc.lob.encodeString(loc.connection, text, loc.charsetID)
+			if loc.lob.variableWidthChar() {
+				amount = int64(len(piece) / 2)
+			} else {
+				amount = int64(utf8.RuneCountInString(text))
+			}
+		}
+		err = loc.writeAt(piece, off, amount)
+		if err != nil {
+			return written, units, err
+		}
+		off += amount
+		units += amount
+		written = end
+	}
+	return written, units, nil
+}
+
+// runeBoundary return the largest index <= end that doesn't split UTF-8
+// character of p. end is returned when no character start is found
+func runeBoundary(p []byte, end int) int {
+	if end >= len(p) {
+		return len(p)
+	}
+	for index := end; index > 0 && index > end-utf8.UTFMax; index-- {
+		if utf8.RuneStart(p[index]) {
+			return index
+		}
+	}
+	return end
+}
+
+// splitPartial split p into complete UTF-8 characters and incomplete
+// character at the end
+func splitPartial(p []byte) ([]byte, []byte) {
+	end := len(p)
+	start := end - 1
+	for start > 0 && start > end-utf8.UTFMax && !utf8.RuneStart(p[start]) {
+		start--
+	}
+	if start >= 0 && !utf8.FullRune(p[start:]) {
+		end = start
+	}
+	return p[:end], p[end:]
+}
+
+// Write implement io.Writer. data is written at the current position and
+// incomplete UTF-8 character at the end of p is kept for the next call or
+// Flush
+func (loc *LobLocator) Write(p []byte) (int, error) {
+	data := p
+	carried := 0
+	if loc.isClob {
+		carried = len(loc.partial)
+		var partial []byte
+		data, partial = splitPartial(append(loc.partial, p...))
+		loc.partial = append([]byte(nil), partial...)
+	}
+	if len(data) == 0 {
+		return len(p), nil
+	}
+	offset := loc.pos - loc.pendingSize()
+	written, units, err := loc.write(data, offset)
+	loc.pos = offset + units
+	if err != nil {
+		// the caller write the rest of p again
+		loc.partial = nil
+		// bytes of previous call are not part of p
+		written -= carried
+		if written < 0 {
+			written = 0
+		}
+		return written, err
+	}
+	return len(p), nil
+}
+
+// Flush return error when Write of CLOB kept incomplete UTF-8 character
+// that is not completed by the next call. it is called when the locator is
+// bound. the kept bytes are not dropped so the character can still be
+// completed by Write
+func (loc *LobLocator) Flush() error {
+	if len(loc.partial) == 0 {
+		return nil
+	}
+	return fmt.Errorf("incomplete UTF-8 character at the end of CLOB data: % x", loc.partial)
+}
+
+// Append write data at the end of the LOB
+func (loc *LobLocator) Append(p []byte) error {
+	size, err := loc.Size()
+	if err != nil {
+		return err
+	}
+	_, err = loc.WriteAt(p, size)
+	return err
+}
+
+// Trim reduce length of the LOB to size bytes for BLOB and characters for
+// CLOB
+func (loc *LobLocator) Trim(size int64) error {
+	if size < 0 {
+		return errors.New("negative LOB size")
+	}
+	if err := loc.checkLease(); err != nil {
+		return err
+	}
+	loc.lob.data.Reset()
+	loc.lob.sourceOffset = 0
+	loc.lob.size = size
+	err := loc.lob.write(loc.connection.session, lobOpTrim)
+	if err == nil {
+		err = loc.lob.read(loc.connection)
+	}
+	loc.size = -1
+	loc.pending = nil
+	return err
+}
+
+// Free release temporary LOB. it do nothing for other LOBs
+func (loc *LobLocator) Free() error {
+	if !loc.temporary {
+		return nil
+	}
+	if err := loc.checkLease(); err != nil {
+		return err
+	}
+	return loc.free()
+}
+
+// free release temporary LOB created by the statement. they are freed with
+// the statement even after the connection is released
+func (loc *LobLocator) free() error {
+	if !loc.temporary || loc.connection.session == nil {
+		return nil
+	}
+	loc.temporary = false
+	return loc.operation(lobOpFreeTemp)
+}
@@ -1,11 +1,20 @@
 package network
 
 import (
+	"context"
+	"crypto/tls"
+	"net"
 	"strconv"
 
 	"github.com/sijms/go-ora/v2/trace"
 )
 
+// DialerContext is implemented by net.Dialer and used to open the
+// underlying network connection
+type DialerContext interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
 type ClientData struct {
 	ProgramPath string
 	ProgramName string
@@ -40,6 +49,8 @@ type ConnectionOption struct {
 	PrefetchRows int
 	SSL          bool
 	SSLVerify    bool
+	TLSConfig    *tls.Config
+	Dialer       DialerContext
 }
 
 func (op *ConnectionOption) ConnectionData() string {
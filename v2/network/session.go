@@ -2,6 +2,7 @@ package network
 
 import (
 	"bytes"
+	"context"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
@@ -13,6 +14,9 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/sijms/go-ora/v2/converters"
 )
@@ -51,6 +55,10 @@ type Session struct {
 	UseBigClrChunks   bool
 	UseBigScn         bool
 	ClrChunkSize      int
+	breakMutex        sync.Mutex
+	breakPending      bool
+	writeMutex        sync.Mutex // serialize writes of the caller and the break
+	broken            int32      // set by network errors
 	SSL               struct {
 		CertificateRequest []*x509.CertificateRequest
 		PrivateKeys        []*rsa.PrivateKey
@@ -147,24 +155,43 @@ func (session *Session) negotiate() {
 			session.SSL.roots.AddCert(cert)
 		}
 	}
-	config := &tls.Config{
-		Certificates: session.SSL.tlsCertificates,
-		RootCAs:      session.SSL.roots,
-		ServerName:   session.connOption.Host,
-	}
-	if !session.connOption.SSLVerify {
-		config.InsecureSkipVerify = true
+	var config *tls.Config
+	if session.connOption.TLSConfig != nil {
+		config = session.connOption.TLSConfig.Clone()
+		if len(config.ServerName) == 0 {
+			config.ServerName = session.connOption.Host
+		}
+	} else {
+		config = &tls.Config{
+			Certificates: session.SSL.tlsCertificates,
+			RootCAs:      session.SSL.roots,
+			ServerName:   session.connOption.Host,
+		}
+		if !session.connOption.SSLVerify {
+			config.InsecureSkipVerify = true
+		}
 	}
 	session.sslConn = tls.Client(session.conn, config)
 	//session.connOption.Tracer.Print("SSL/TLS HandShake complete")
 }
 
+// Connect open connection to the server
 func (session *Session) Connect() error {
+	return session.ConnectContext(context.Background())
+}
+
+// ConnectContext open connection to the server. ctx control dialing
+func (session *Session) ConnectContext(ctx context.Context) error {
 	session.Disconnect()
+	atomic.StoreInt32(&session.broken, 0)
 	session.connOption.Tracer.Print("Connect")
 	var err error
 	addr := fmt.Sprintf("%s:%d", session.connOption.Host, session.connOption.Port)
-	session.conn, err = net.Dial("tcp", addr)
+	dialer := session.connOption.Dialer
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+	session.conn, err = dialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
 		return err
 	}
@@ -211,7 +238,7 @@ func (session *Session) Connect() error {
 				return errors.New("redirect packet with wrong port")
 			}
 		}
-		return session.Connect()
+		return session.ConnectContext(ctx)
 	}
 	if refusePacket, ok := pck.(*RefusePacket); ok {
 		errorMessage := fmt.Sprintf(
@@ -251,6 +278,121 @@ func (session *Session) Disconnect() {
 	}
 }
 
+// CallContext track one round trip that can be interrupted through context
+type CallContext struct {
+	done     chan struct{}
+	finished bool
+}
+
+// StartContext watch ctx during a round trip. when ctx is done before
+// EndContext is called an out-of-band break is sent to the server so the
+// pending read return with the server error instead of blocking
+func (session *Session) StartContext(ctx context.Context) *CallContext {
+	call := &CallContext{done: make(chan struct{})}
+	if ctx.Done() == nil {
+		return call
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			session.breakMutex.Lock()
+			defer session.breakMutex.Unlock()
+			if !call.finished {
+				session.connOption.Tracer.Print("Break Connection: ", ctx.Err())
+				if err := session.sendBreak(); err == nil {
+					session.breakPending = true
+				}
+			}
+		case <-call.done:
+		}
+	}()
+	return call
+}
+
+// breakDrainTimeout limit the wait for the server answer to a break
+const breakDrainTimeout = 10 * time.Second
+
+// EndContext stop watching the context started by StartContext. if a break
+// was sent after the server completed the call the reset handshake is
+// drained here so the session is ready for the next call. the session is
+// marked broken when the server does not answer the break
+func (session *Session) EndContext(call *CallContext) error {
+	session.breakMutex.Lock()
+	call.finished = true
+	pending := session.breakPending
+	session.breakMutex.Unlock()
+	close(call.done)
+	if !pending {
+		return nil
+	}
+	session.connOption.Tracer.Print("Drain Break")
+	conn := session.netConn()
+	if conn == nil {
+		return errors.New("connection is closed")
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(breakDrainTimeout))
+	defer func() {
+		_ = conn.SetReadDeadline(time.Time{})
+	}()
+	for {
+		// the server answer the break with marker packets followed by
+		// ORA-01013. readPacket consume both and clear breakPending
+		_, err := session.readPacket()
+		session.breakMutex.Lock()
+		pending = session.breakPending
+		session.breakMutex.Unlock()
+		if !pending {
+			session.ResetBuffer()
+			return nil
+		}
+		if err != nil {
+			if _, ok := err.(*OracleError); !ok {
+				atomic.StoreInt32(&session.broken, 1)
+				return err
+			}
+		}
+	}
+}
+
+// sendBreak write break marker to the server without touching the packets
+// saved for resend
+func (session *Session) sendBreak() error {
+	pck := newMarkerPacket(1, session.Context)
+	return session.writeData(pck.bytes())
+}
+
+// netConn return the connection used for reading and writing
+func (session *Session) netConn() net.Conn {
+	if session.sslConn != nil {
+		return session.sslConn
+	}
+	if session.conn != nil {
+		return session.conn
+	}
+	return nil
+}
+
+// writeData write to the socket. network errors mark the session broken
+func (session *Session) writeData(data []byte) error {
+	session.writeMutex.Lock()
+	defer session.writeMutex.Unlock()
+	conn := session.netConn()
+	if conn == nil {
+		return errors.New("connection is closed")
+	}
+	_, err := conn.Write(data)
+	if err != nil {
+		atomic.StoreInt32(&session.broken, 1)
+	}
+	return err
+}
+
+// IsBroken return true when a network error or unanswered break left the
+// session in unknown state so it should not be reused
+func (session *Session) IsBroken() bool {
+	return atomic.LoadInt32(&session.broken) != 0
+}
+
 func (session *Session) ResetBuffer() {
 	session.Summary = nil
 	session.sendPcks = nil
@@ -260,6 +402,14 @@ func (session *Session) ResetBuffer() {
 	session.index = 0
 }
 
+// SetBuffer replace received data with buffer so images kept outside of
+// the packets are decoded with session functions. reads after the end of
+// buffer continue from the network
+func (session *Session) SetBuffer(buffer []byte) {
+	session.inBuffer = buffer
+	session.index = 0
+}
+
 func (session *Session) Debug() {
 	//if session.index > 350 && session.index < 370 {
 	fmt.Println("index: ", session.index)
@@ -345,13 +495,7 @@ func (session *Session) writePacket(pck PacketInterface) error {
 	session.sendPcks = append(session.sendPcks, pck)
 	tmp := pck.bytes()
 	session.connOption.Tracer.LogPacket("Write packet:", tmp)
-	var err error
-	if session.sslConn != nil {
-		_, err = session.sslConn.Write(tmp)
-	} else {
-		_, err = session.conn.Write(tmp)
-	}
-	return err
+	return session.writeData(tmp)
 }
 
 func (session *Session) HasError() bool {
@@ -389,6 +533,7 @@ func (session *Session) readPacket() (PacketInterface, error) {
 			}
 			//_, err := conn.Read(head)
 			if err != nil {
+				atomic.StoreInt32(&session.broken, 1)
 				return nil, err
 			}
 			pckType := PacketType(head[4])
@@ -414,6 +559,7 @@ func (session *Session) readPacket() (PacketInterface, error) {
 						index += uint32(temp)
 						continue
 					}
+					atomic.StoreInt32(&session.broken, 1)
 					return nil, err
 				}
 				index += uint32(temp)
@@ -422,13 +568,10 @@ func (session *Session) readPacket() (PacketInterface, error) {
 			if pckType == RESEND {
 				for _, pck := range session.sendPcks {
 					//log.Printf("Request: %#v\n\n", pck.bytes())
-					var err error
 					if session.connOption.SSL {
 						session.negotiate()
-						_, err = session.sslConn.Write(pck.bytes())
-					} else {
-						_, err = session.conn.Write(pck.bytes())
 					}
+					err := session.writeData(pck.bytes())
 					if err != nil {
 						return nil, err
 					}
@@ -531,6 +674,9 @@ func (session *Session) readPacket() (PacketInterface, error) {
 			}
 			trials++
 		}
+		session.breakMutex.Lock()
+		session.breakPending = false
+		session.breakMutex.Unlock()
 		session.ResetBuffer()
 		err = session.writePacket(newMarkerPacket(2, session.Context))
 		if err != nil {
@@ -588,6 +734,16 @@ func (session *Session) PutBytes(data ...byte) {
 	//session.outBuffer = append(session.outBuffer, )
 }
 
+// PutFunctionCode write header of function message: message code, function
+// code and sequence number. TTC field version 18 (23c ext 1) add token
+// number that is not used
+func (session *Session) PutFunctionCode(messageCode, functionCode uint8) {
+	session.PutBytes(messageCode, functionCode, 0)
+	if session.TTCVersion >= 18 {
+		session.PutUint(0, 8, true, true)
+	}
+}
+
 //func (session *Session) PutByte(num byte) {
 //		session.outBuffer = append(session.outBuffer, num)
 //}
@@ -0,0 +1,82 @@
+package network
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/sijms/go-ora/v2/trace"
+)
+
+func TestSessionBroken(t *testing.T) {
+	option := &ConnectionOption{}
+	option.Tracer = trace.NilTracer()
+	session := NewSession(option)
+	client, server := net.Pipe()
+	session.conn = client
+	go func() {
+		buffer := make([]byte, 10)
+		_, _ = server.Read(buffer)
+	}()
+	if err := session.writeData([]byte{1}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if session.IsBroken() {
+		t.Errorf("session is broken after successful write")
+	}
+	_ = server.Close()
+	if err := session.writeData([]byte{1}); err == nil {
+		t.Errorf("write to closed connection should fail")
+	}
+	if !session.IsBroken() {
+		t.Errorf("session should be broken after network error")
+	}
+	session.Disconnect()
+}
+
+func TestNewSummaryFieldVersion(t *testing.T) {
+	option := &ConnectionOption{}
+	option.Tracer = trace.NilTracer()
+	session := NewSession(option)
+	session.TTCVersion = 17
+	session.inBuffer = []byte{
+		0, 0, 0, 0, 0, 0, // current row, return code, array errors, cursor, error position
+		0, 0, // sql type and fatal flag
+		0, 0, // flags and cursor options
+		0, 0, // upi parameter and warning flag
+		0, 0, 0, 0, 0, // rowid
+		0, 0, 0, 0, // os error, statement and call number, padding
+		0, 0, // success iterations and oerrdd
+		0, 0, 0, // batch error codes, offsets and messages
+		2, 0x03, 0xAE, 0, // return code 942 and row number
+		1, 5, 1, 7, // sql type and server checksum
+		3, 'a', 'b', 'c',
+	}
+	summary, err := NewSummary(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.RetCode != 942 || string(summary.ErrorMessage) != "abc" {
+		t.Errorf("summary = %d %q, want 942 \"abc\"", summary.RetCode, summary.ErrorMessage)
+	}
+	if session.index != len(session.inBuffer) {
+		t.Errorf("summary read %d bytes of %d", session.index, len(session.inBuffer))
+	}
+}
+
+func TestPutFunctionCode(t *testing.T) {
+	option := &ConnectionOption{}
+	option.Tracer = trace.NilTracer()
+	session := NewSession(option)
+	session.TTCVersion = 17
+	session.PutFunctionCode(3, 0x5E)
+	if got := session.outBuffer.Bytes(); !bytes.Equal(got, []byte{3, 0x5E, 0}) {
+		t.Errorf("field version 17 header = %v", got)
+	}
+	session.ResetBuffer()
+	session.TTCVersion = 18
+	session.PutFunctionCode(3, 0x5E)
+	if got := session.outBuffer.Bytes(); !bytes.Equal(got, []byte{3, 0x5E, 0, 0}) {
+		t.Errorf("field version 18 header = %v, want token number", got)
+	}
+}
@@ -1,9 +1,11 @@
 package network
 
+// BindError is the error of one iteration of array DML executed with
+// batch errors option
 type BindError struct {
-	errorCode int
-	rowOffset int
-	errorMsg  []byte
+	ErrorCode int
+	RowOffset int
+	ErrorMsg  []byte
 }
 type SummaryObject struct {
 	EndOfCallStatus      int // uint32
@@ -31,7 +33,12 @@ type SummaryObject struct {
 	pad1                 int // uint16
 	successIter          int // uint16
 	ErrorMessage         []byte
-	bindErrors           []BindError
+	BindErrors           []BindError
+}
+
+// RowID return parts of the rowid of the last row affected by the call
+func (summary *SummaryObject) RowID() (rba, partitionID, blockNumber, slotNumber int) {
+	return summary.rba, summary.partitionID, summary.blockNumber, summary.slotNumber
 }
 
 func NewSummary(session *Session) (*SummaryObject, error) {
@@ -143,7 +150,7 @@ func NewSummary(session *Session) (*SummaryObject, error) {
 		return nil, err
 	}
 	if length > 0 {
-		result.bindErrors = make([]BindError, length)
+		result.BindErrors = make([]BindError, length)
 		num, err := session.GetByte()
 		if err != nil {
 			return nil, err
@@ -157,7 +164,7 @@ func NewSummary(session *Session) (*SummaryObject, error) {
 					_, _ = session.GetByte()
 				}
 			}
-			result.bindErrors[x].errorCode, err = session.GetInt(2, true, true)
+			result.BindErrors[x].ErrorCode, err = session.GetInt(2, true, true)
 			if err != nil {
 				return nil, err
 			}
@@ -170,6 +177,9 @@ func NewSummary(session *Session) (*SummaryObject, error) {
 	if err != nil {
 		return nil, err
 	}
+	if length > len(result.BindErrors) {
+		result.BindErrors = append(result.BindErrors, make([]BindError, length-len(result.BindErrors))...)
+	}
 	if length > 0 {
 		num, err := session.GetByte()
 		if err != nil {
@@ -184,7 +194,7 @@ func NewSummary(session *Session) (*SummaryObject, error) {
 					_, _ = session.GetByte()
 				}
 			}
-			result.bindErrors[x].rowOffset, err = session.GetInt(4, true, true)
+			result.BindErrors[x].RowOffset, err = session.GetInt(4, true, true)
 			if err != nil {
 				return nil, err
 			}
@@ -197,6 +207,9 @@ func NewSummary(session *Session) (*SummaryObject, error) {
 	if err != nil {
 		return nil, err
 	}
+	if length > len(result.BindErrors) {
+		result.BindErrors = append(result.BindErrors, make([]BindError, length-len(result.BindErrors))...)
+	}
 	if length > 0 {
 		_, _ = session.GetByte()
 		for x := 0; x < length; x++ {
@@ -204,7 +217,7 @@ func NewSummary(session *Session) (*SummaryObject, error) {
 			if err != nil {
 				return nil, err
 			}
-			result.bindErrors[x].errorMsg, err = session.GetClr()
+			result.BindErrors[x].ErrorMsg, err = session.GetClr()
 			if err != nil {
 				return nil, err
 			}
@@ -222,6 +235,17 @@ func NewSummary(session *Session) (*SummaryObject, error) {
 			return nil, err
 		}
 	}
+	if session.TTCVersion >= 14 {
+		// sql type and server checksum
+		_, err = session.GetInt(4, true, true)
+		if err != nil {
+			return nil, err
+		}
+		_, err = session.GetInt(4, true, true)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if result.RetCode != 0 {
 		result.ErrorMessage, err = session.GetClr()
 		if err != nil {
@@ -40,6 +40,8 @@ func _() {
 	_ = x[OCIBlobLocator-113]
 	_ = x[OCIFileLocator-114]
 	_ = x[ResultSet-116]
+	_ = x[JSON-119]
+	_ = x[VECTOR-127]
 	_ = x[OCIString-155]
 	_ = x[OCIDate-156]
 	_ = x[TimeStampDTY-180]
@@ -54,9 +56,10 @@ func _() {
 	_ = x[UROWID-208]
 	_ = x[TimeStampLTZ_DTY-231]
 	_ = x[TimeStampeLTZ-232]
+	_ = x[BOOLEAN-252]
 }
 
-const _OracleType_name = "NCHARNUMBERSB1FLOATNullStrVarNumLONGVARCHARROWIDDATEVarRawBFloatBDoubleRAWLongRawUINTLongVarCharLongVarRawCHARCHARZIBFloatIBDoubleRefCursorOCIXMLTypeOCIRefOCIClobLocatorOCIBlobLocatorOCIFileLocatorResultSetOCIStringOCIDateTimeStampDTYTimeStampTZ_DTYIntervalYM_DTYIntervalDS_DTYTimeTZTimeStampTimeStampTZIntervalYMIntervalDSUROWIDTimeStampLTZ_DTYTimeStampeLTZ"
+const _OracleType_name = "NCHARNUMBERSB1FLOATNullStrVarNumLONGVARCHARROWIDDATEVarRawBFloatBDoubleRAWLongRawUINTLongVarCharLongVarRawCHARCHARZIBFloatIBDoubleREFCURSOROCIXMLTypeXMLTypeOCIRefOCIClobLocatorOCIBlobLocatorOCIFileLocatorResultSetJSONVECTOROCIStringOCIDateTimeStampDTYTimeStampTZ_DTYIntervalYM_DTYIntervalDS_DTYTimeTZTimeStampTimeStampTZIntervalYMIntervalDSUROWIDTimeStampLTZ_DTYTimeStampeLTZBOOLEAN"
 
 var _OracleType_map = map[OracleType]string{
 	1:   _OracleType_name[0:5],
@@ -82,26 +85,30 @@ var _OracleType_map = map[OracleType]string{
 	100: _OracleType_name[115:122],
 	101: _OracleType_name[122:130],
 	102: _OracleType_name[130:139],
-	108: _OracleType_name[139:148],
-	110: _OracleType_name[148:154],
-	112: _OracleType_name[154:168],
-	113: _OracleType_name[168:183],
-	114: _OracleType_name[183:196],
-	116: _OracleType_name[196:205],
-	155: _OracleType_name[205:214],
-	156: _OracleType_name[214:221],
-	180: _OracleType_name[221:233],
-	181: _OracleType_name[233:248],
-	182: _OracleType_name[248:264],
-	183: _OracleType_name[264:276],
-	186: _OracleType_name[276:282],
-	187: _OracleType_name[282:291],
-	188: _OracleType_name[291:302],
-	189: _OracleType_name[302:312],
-	190: _OracleType_name[312:322],
-	208: _OracleType_name[322:328],
-	231: _OracleType_name[328:344],
-	232: _OracleType_name[344:357],
+	108: _OracleType_name[139:149],
+	109: _OracleType_name[149:156],
+	110: _OracleType_name[156:162],
+	112: _OracleType_name[162:176],
+	113: _OracleType_name[176:190],
+	114: _OracleType_name[190:204],
+	116: _OracleType_name[204:213],
+	119: _OracleType_name[213:217],
+	127: _OracleType_name[217:223],
+	155: _OracleType_name[223:232],
+	156: _OracleType_name[232:239],
+	180: _OracleType_name[239:251],
+	181: _OracleType_name[251:266],
+	182: _OracleType_name[266:280],
+	183: _OracleType_name[280:294],
+	186: _OracleType_name[294:300],
+	187: _OracleType_name[300:309],
+	188: _OracleType_name[309:320],
+	189: _OracleType_name[320:330],
+	190: _OracleType_name[330:340],
+	208: _OracleType_name[340:346],
+	231: _OracleType_name[346:362],
+	232: _OracleType_name[362:375],
+	252: _OracleType_name[375:382],
 }
 
 func (i OracleType) String() string {
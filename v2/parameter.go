@@ -1,10 +1,18 @@
 package go_ora
 
 import (
+	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
+	"math/big"
+	"reflect"
 	"strings"
+	"time"
 
+	"github.com/sijms/go-ora/v2/converters"
 	"github.com/sijms/go-ora/v2/network"
 )
 
@@ -12,6 +20,17 @@ type OracleType int
 type ParameterDirection int
 type NVarChar string
 
+// Date is time.Time bound as DATE. time.Time is bound as TIMESTAMP which
+// convert DATE column in comparison and prevent use of its index
+//
+//	db.Query("SELECT * FROM T1 WHERE CREATED >= :1", go_ora.Date(since))
+type Date time.Time
+
+// BinaryFloat and BinaryDouble are bound as BINARY_FLOAT and BINARY_DOUBLE.
+// float32 and float64 are bound as NUMBER
+type BinaryFloat float32
+type BinaryDouble float64
+
 //func (n *NVarChar) ConvertValue(v interface{}) (driver.Value, error) {
 //	return driver.Value(string(*n)), nil
 //}
@@ -69,6 +88,8 @@ const (
 	OCIBlobLocator   OracleType = 113
 	OCIFileLocator   OracleType = 114
 	ResultSet        OracleType = 116
+	JSON             OracleType = 119
+	VECTOR           OracleType = 127
 	OCIString        OracleType = 155
 	OCIDate          OracleType = 156
 	TimeStampDTY     OracleType = 180
@@ -83,6 +104,7 @@ const (
 	UROWID           OracleType = 208
 	TimeStampLTZ_DTY OracleType = 231
 	TimeStampeLTZ    OracleType = 232
+	BOOLEAN          OracleType = 252
 )
 
 type ParameterType int
@@ -117,6 +139,385 @@ type ParameterInfo struct {
 	getDataFromServer    bool
 	oaccollid            int
 	cusType              *customType
+	outDest              interface{}
+	arrayBValues         [][]byte
+	returnArray          bool
+	// VectorDimensions and VectorFormat describe VECTOR columns. zero
+	// values mean flexible dimensions and format
+	VectorDimensions int
+	VectorFormat     converters.VectorFormat
+}
+
+// Out is like sql.Out with size hint for string and []byte destinations.
+// Size is the maximum number of characters for strings and bytes for []byte
+//
+//	var name string
+//	_, err = db.Exec("BEGIN get_name(:id, :name); END;", sql.Named("id", 1),
+//		sql.Named("name", go_ora.Out{Dest: &name, Size: 100}))
+type Out struct {
+	Dest interface{}
+	Size int
+	In   bool
+}
+
+// default buffer size for string and []byte output parameters without size
+const defaultOutSize = 4000
+
+// parseBindNames return placeholder names in the order the server expect
+// their values. sql statements need a value for each occurrence while
+// pl/sql blocks need one value for each distinct name. names are returned
+// in upper case unless they are quoted
+func parseBindNames(text string, isPLSQL bool) []string {
+	var names []string
+	seen := map[string]bool{}
+	isNameChar := func(ch byte) bool {
+		return ch == '_' || ch == '$' || ch == '#' || (ch >= '0' && ch <= '9') ||
+			(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || ch >= 0x80
+	}
+	for x := 0; x < len(text); x++ {
+		switch text[x] {
+		case '\'':
+			if isQuoteLiteral(text, x, isNameChar) && x+1 < len(text) {
+				// q'[...]' literal end with closing delimiter and quote
+				closing := text[x+1]
+				switch closing {
+				case '[':
+					closing = ']'
+				case '{':
+					closing = '}'
+				case '<':
+					closing = '>'
+				case '(':
+					closing = ')'
+				}
+				end := strings.Index(text[x+2:], string([]byte{closing, '\''}))
+				if end < 0 {
+					return names
+				}
+				x += end + 3
+				continue
+			}
+			end := strings.IndexByte(text[x+1:], '\'')
+			if end < 0 {
+				return names
+			}
+			x += end + 1
+		case '"':
+			end := strings.IndexByte(text[x+1:], '"')
+			if end < 0 {
+				return names
+			}
+			x += end + 1
+		case '-':
+			if x+1 < len(text) && text[x+1] == '-' {
+				end := strings.IndexByte(text[x:], '\n')
+				if end < 0 {
+					return names
+				}
+				x += end
+			}
+		case '/':
+			if x+1 < len(text) && text[x+1] == '*' {
+				end := strings.Index(text[x+2:], "*/")
+				if end < 0 {
+					return names
+				}
+				x += end + 3
+			}
+		case ':':
+			start := x + 1
+			var name string
+			if start < len(text) && text[start] == '"' {
+				end := strings.IndexByte(text[start+1:], '"')
+				if end < 0 {
+					return names
+				}
+				name = text[start+1 : start+1+end]
+				x = start + end + 1
+			} else {
+				end := start
+				for end < len(text) && isNameChar(text[end]) {
+					end++
+				}
+				if end == start {
+					// := or ::
+					continue
+				}
+				name = strings.ToUpper(text[start:end])
+				x = end - 1
+			}
+			if isPLSQL && seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// isQuoteLiteral report if the quote at index open q-quote or nq-quote literal
+func isQuoteLiteral(text string, index int, isNameChar func(byte) bool) bool {
+	if index < 1 || (text[index-1] != 'q' && text[index-1] != 'Q') {
+		return false
+	}
+	prefix := index - 2
+	if prefix >= 0 && (text[prefix] == 'n' || text[prefix] == 'N') {
+		prefix--
+	}
+	return prefix < 0 || !isNameChar(text[prefix])
+}
+
+// orderNamedArgs return one argument for each placeholder in names
+func orderNamedArgs(names []string, args []driver.NamedValue) ([]driver.NamedValue, error) {
+	used := make([]bool, len(args))
+	output := make([]driver.NamedValue, 0, len(names))
+	for _, name := range names {
+		found := false
+		for x, arg := range args {
+			if arg.Name == name || strings.EqualFold(arg.Name, name) {
+				output = append(output, driver.NamedValue{Name: arg.Name, Ordinal: len(output) + 1, Value: arg.Value})
+				used[x] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("missing value for bind parameter :%s", name)
+		}
+	}
+	for x, arg := range args {
+		if !used[x] {
+			return nil, fmt.Errorf("bind parameter :%s is not found in the sql text", arg.Name)
+		}
+	}
+	return output, nil
+}
+
+// outValue return the value used to bind output destination. for output only
+// parameters the zero value of the destination type is returned so the
+// parameter get the correct data type
+func outValue(dest interface{}, in bool) (driver.Value, error) {
+	if dest == nil {
+		return nil, errors.New("output destination cannot be nil")
+	}
+	destVal := reflect.ValueOf(dest)
+	if destVal.Kind() != reflect.Ptr || destVal.IsNil() {
+		return nil, fmt.Errorf("output destination should be a non nil pointer: %T", dest)
+	}
+	if isArrayBind(destVal.Elem().Interface()) {
+		// rows of returning clause
+		if in {
+			return nil, fmt.Errorf("slice destination cannot be input: %T", dest)
+		}
+		return outValue(reflect.New(destVal.Elem().Type().Elem()).Interface(), false)
+	}
+	val := destVal.Elem().Interface()
+	switch val := val.(type) {
+	case sql.NullString:
+		if in && val.Valid {
+			return val.String, nil
+		}
+		return "", nil
+	case sql.NullInt64:
+		if in && val.Valid {
+			return val.Int64, nil
+		}
+		return int64(0), nil
+	case sql.NullInt32:
+		if in && val.Valid {
+			return val.Int32, nil
+		}
+		return int64(0), nil
+	case sql.NullFloat64:
+		if in && val.Valid {
+			return val.Float64, nil
+		}
+		return float64(0), nil
+	case sql.NullTime:
+		if in && val.Valid {
+			return val.Time, nil
+		}
+		return time.Time{}, nil
+	case string, NVarChar, []byte, time.Time, float32, float64, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, bool,
+		time.Duration, converters.IntervalYM, BinaryFloat, BinaryDouble, Decimal,
+		json.RawMessage, map[string]interface{}, JSONValue, Vector, XML:
+		return val, nil
+	default:
+		return nil, fmt.Errorf("unsupported output destination type: %T", dest)
+	}
+}
+
+// exactNumberOutput return true when NUMBER output into dest should be
+// decoded as Decimal. float64 cannot hold all digits of Decimal and unsigned
+// destinations above math.MaxInt64
+func exactNumberOutput(dest interface{}) bool {
+	if dest == nil {
+		return false
+	}
+	if _, ok := dest.(*Decimal); ok {
+		return true
+	}
+	if _, ok := dest.(sql.Scanner); ok {
+		return false
+	}
+	destType := reflect.TypeOf(dest)
+	if destType.Kind() != reflect.Ptr {
+		return false
+	}
+	destType = destType.Elem()
+	if destType.Kind() == reflect.Slice {
+		// returning clause
+		destType = destType.Elem()
+	}
+	switch destType.Kind() {
+	case reflect.Uint, reflect.Uint64:
+		return true
+	}
+	return destType == reflect.TypeOf(Decimal{})
+}
+
+// setOutValue write output parameter value into its destination
+func setOutValue(dest interface{}, value driver.Value) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+	if raw, ok := dest.(*json.RawMessage); ok {
+		// JSON value is decoded from OSON
+		if value == nil {
+			*raw = nil
+			return nil
+		}
+		temp, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		*raw = temp
+		return nil
+	}
+	destVal := reflect.ValueOf(dest).Elem()
+	if value == nil {
+		destVal.Set(reflect.Zero(destVal.Type()))
+		return nil
+	}
+	// exact types first so named types like time.Duration are not handled
+	// as their underlying kind
+	valueVal := reflect.ValueOf(value)
+	if valueVal.Type().AssignableTo(destVal.Type()) {
+		destVal.Set(valueVal)
+		return nil
+	}
+	switch destVal.Kind() {
+	case reflect.String:
+		if temp, ok := value.(string); ok {
+			destVal.SetString(temp)
+			return nil
+		}
+		destVal.SetString(fmt.Sprint(value))
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var temp int64
+		switch val := value.(type) {
+		case int64:
+			temp = val
+		case float64:
+			if val != math.Trunc(val) || val < math.MinInt64 || val >= math.MaxInt64 {
+				return fmt.Errorf("value %v cannot be stored in output destination of type %T", val, dest)
+			}
+			temp = int64(val)
+		case Decimal:
+			var err error
+			temp, err = val.Int64()
+			if err != nil {
+				return fmt.Errorf("value %s cannot be stored in output destination of type %T", val, dest)
+			}
+		default:
+			return fmt.Errorf("cannot assign value of type %T to output destination of type %T", value, dest)
+		}
+		if destVal.OverflowInt(temp) {
+			return fmt.Errorf("value %d overflows output destination of type %T", temp, dest)
+		}
+		destVal.SetInt(temp)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var temp uint64
+		switch val := value.(type) {
+		case int64:
+			if val < 0 {
+				return fmt.Errorf("value %d cannot be stored in output destination of type %T", val, dest)
+			}
+			temp = uint64(val)
+		case uint64:
+			temp = val
+		case float64:
+			if val != math.Trunc(val) || val < 0 || val >= math.Exp2(64) {
+				return fmt.Errorf("value %v cannot be stored in output destination of type %T", val, dest)
+			}
+			temp = uint64(val)
+		case Decimal:
+			num, err := val.Int()
+			if err != nil || !num.IsUint64() {
+				return fmt.Errorf("value %s cannot be stored in output destination of type %T", val, dest)
+			}
+			temp = num.Uint64()
+		default:
+			return fmt.Errorf("cannot assign value of type %T to output destination of type %T", value, dest)
+		}
+		if destVal.OverflowUint(temp) {
+			return fmt.Errorf("value %d overflows output destination of type %T", temp, dest)
+		}
+		destVal.SetUint(temp)
+		return nil
+	case reflect.Bool:
+		switch temp := value.(type) {
+		case int64:
+			destVal.SetBool(temp != 0)
+			return nil
+		case float64:
+			destVal.SetBool(temp != 0)
+			return nil
+		case bool:
+			destVal.SetBool(temp)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch temp := value.(type) {
+		case int64:
+			destVal.SetFloat(float64(temp))
+			return nil
+		case float64:
+			destVal.SetFloat(temp)
+			return nil
+		case float32:
+			destVal.SetFloat(float64(temp))
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot assign value of type %T to output destination of type %T", value, dest)
+}
+
+// setOutSlice write rows returned for output parameter into slice
+// destination
+func setOutSlice(dest interface{}, values []driver.Value) error {
+	destVal := reflect.ValueOf(dest).Elem()
+	ret := reflect.MakeSlice(destVal.Type(), len(values), len(values))
+	for x, value := range values {
+		err := setOutValue(ret.Index(x).Addr().Interface(), value)
+		if err != nil {
+			return err
+		}
+	}
+	destVal.Set(ret)
+	return nil
+}
+
+func toNamedValues(args []driver.Value) []driver.NamedValue {
+	output := make([]driver.NamedValue, len(args))
+	for x := 0; x < len(args); x++ {
+		output[x] = driver.NamedValue{Ordinal: x + 1, Value: args[x]}
+	}
+	return output
 }
 
 func (par *ParameterInfo) load(conn *Connection) error {
@@ -193,9 +594,9 @@ func (par *ParameterInfo) load(conn *Connection) error {
 		par.MaxLen = 13
 	case IntervalYM_DTY:
 		fallthrough
-	case IntervalDS_DTY:
-		fallthrough
 	case IntervalYM:
+		par.MaxLen = 5
+	case IntervalDS_DTY:
 		fallthrough
 	case IntervalDS:
 		par.MaxLen = 11
@@ -271,8 +672,87 @@ func (par *ParameterInfo) load(conn *Connection) error {
 		return nil
 	}
 	_, err = session.GetInt(4, true, true)
+	if err != nil {
+		return err
+	}
+	if session.TTCVersion >= 17 {
+		// domain schema and name
+		_, err = session.GetDlc()
+		if err != nil {
+			return err
+		}
+		_, err = session.GetDlc()
+		if err != nil {
+			return err
+		}
+	}
+	if session.TTCVersion >= 20 {
+		err = par.loadAnnotations(session)
+		if err != nil {
+			return err
+		}
+	}
+	if session.TTCVersion >= 24 {
+		par.VectorDimensions, err = session.GetInt(4, true, true)
+		if err != nil {
+			return err
+		}
+		format, err := session.GetByte()
+		if err != nil {
+			return err
+		}
+		par.VectorFormat = converters.VectorFormat(format)
+		flags, err := session.GetByte()
+		if err != nil {
+			return err
+		}
+		if flags&vectorFlexibleDimensions != 0 {
+			par.VectorDimensions = 0
+		}
+	}
 	return nil
 }
+
+// vectorFlexibleDimensions is set in VECTOR column flags when the number of
+// dimensions is not fixed
+const vectorFlexibleDimensions = 1
+
+// loadAnnotations skip column annotations
+func (par *ParameterInfo) loadAnnotations(session *network.Session) error {
+	count, err := session.GetInt(4, true, true)
+	if err != nil || count == 0 {
+		return err
+	}
+	_, err = session.GetByte()
+	if err != nil {
+		return err
+	}
+	count, err = session.GetInt(4, true, true)
+	if err != nil {
+		return err
+	}
+	_, err = session.GetByte()
+	if err != nil {
+		return err
+	}
+	for x := 0; x < count; x++ {
+		// key, value and flags
+		_, err = session.GetDlc()
+		if err != nil {
+			return err
+		}
+		_, err = session.GetDlc()
+		if err != nil {
+			return err
+		}
+		_, err = session.GetInt(4, true, true)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = session.GetInt(4, true, true)
+	return err
+}
 func (par *ParameterInfo) write(session *network.Session) error {
 	session.PutBytes(uint8(par.DataType), par.Flag, par.Precision, par.Scale)
 	//session.PutUint(int(par.DataType), 1, false, false)
@@ -384,3 +864,51 @@ func (par *ParameterInfo) write(session *network.Session) error {
 //	//}
 //	//return ret
 //}
+
+// arrayBindSize return number of rows when arguments are slices bound as
+// arrays. []byte is a scalar RAW value
+func arrayBindSize(args []driver.NamedValue) (int, error) {
+	count, arrays := 0, 0
+	for _, arg := range args {
+		if !isArrayBind(arg.Value) {
+			continue
+		}
+		size := reflect.ValueOf(arg.Value).Len()
+		if arrays > 0 && size != count {
+			return 0, errors.New("all array parameters should have the same length")
+		}
+		count = size
+		arrays++
+	}
+	if arrays == 0 {
+		return 0, nil
+	}
+	if arrays < len(args) {
+		return 0, errors.New("mixing array and scalar parameters is not allowed")
+	}
+	if count == 0 {
+		return 0, errors.New("array parameters should not be empty")
+	}
+	return count, nil
+}
+
+func isArrayBind(val driver.Value) bool {
+	if val == nil {
+		return false
+	}
+	rType := reflect.TypeOf(val)
+	return rType.Kind() == reflect.Slice && rType.Elem().Kind() != reflect.Uint8
+}
+
+// arrayElemValue convert slice element like sql.NullString or pointer to
+// value accepted by NewParam
+func arrayElemValue(val interface{}) (driver.Value, error) {
+	switch val.(type) {
+	case nil, int64, int32, int16, int8, int, uint64, uint32, uint16, uint8, uint, bool,
+		float32, float64, time.Time, Date, NVarChar, string, []byte,
+		time.Duration, converters.IntervalYM, BinaryFloat, BinaryDouble, Decimal, *big.Int, *big.Float,
+		json.RawMessage, map[string]interface{}, JSONValue, Vector, XML, *LobLocator:
+		return val, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(val)
+}
@@ -0,0 +1,253 @@
+package go_ora
+
+import (
+	"database/sql/driver"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sijms/go-ora/v2/converters"
+)
+
+func TestParseBindNames(t *testing.T) {
+	tests := []struct {
+		text    string
+		isPLSQL bool
+		want    []string
+	}{
+		{"SELECT * FROM T1 WHERE ID = :id AND NAME = :Name", false, []string{"ID", "NAME"}},
+		{"SELECT :1, :2 FROM DUAL", false, []string{"1", "2"}},
+		{"SELECT * FROM T1 WHERE A = :x OR B = :x", false, []string{"X", "X"}},
+		{"BEGIN P1(:x, :y, :x); END;", true, []string{"X", "Y"}},
+		{`SELECT :"MixedCase" FROM DUAL`, false, []string{"MixedCase"}},
+		{"SELECT ':skip' || :a FROM DUAL", false, []string{"A"}},
+		{`SELECT ":skip" FROM T1 WHERE A = :a`, false, []string{"A"}},
+		{"SELECT 1 FROM DUAL -- :skip\nWHERE 1 = :a", false, []string{"A"}},
+		{"SELECT /* :skip */ :a FROM DUAL", false, []string{"A"}},
+		{"SELECT q'[it's :skip]' || :a FROM DUAL", false, []string{"A"}},
+		{"SELECT Q'{:skip}' , nq'<:skip>', q'!:skip'!' || :a FROM DUAL", false, []string{"A"}},
+		{"SELECT q'(:skip)', NQ'#:skip#', :a FROM DUAL", false, []string{"A"}},
+		{"SELECT colq':a' FROM DUAL", false, nil},
+		{"BEGIN :x := 1; y := :x; END;", true, []string{"X"}},
+		{"SELECT '::' || :a FROM DUAL WHERE B = 'unterminated", false, []string{"A"}},
+	}
+	for _, test := range tests {
+		got := parseBindNames(test.text, test.isPLSQL)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("parseBindNames(%q) = %v, want %v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestOrderNamedArgs(t *testing.T) {
+	args := []driver.NamedValue{
+		{Name: "name", Ordinal: 1, Value: "a"},
+		{Name: "ID", Ordinal: 2, Value: int64(1)},
+	}
+	got, err := orderNamedArgs([]string{"ID", "NAME", "ID"}, args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	want := []driver.NamedValue{
+		{Name: "ID", Ordinal: 1, Value: int64(1)},
+		{Name: "name", Ordinal: 2, Value: "a"},
+		{Name: "ID", Ordinal: 3, Value: int64(1)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("orderNamedArgs = %v, want %v", got, want)
+	}
+	if _, err = orderNamedArgs([]string{"ID", "OTHER"}, args[1:]); err == nil {
+		t.Errorf("orderNamedArgs expected error for missing value")
+	}
+	if _, err = orderNamedArgs([]string{"ID"}, args); err == nil {
+		t.Errorf("orderNamedArgs expected error for unused argument")
+	}
+}
+
+func TestSetOutValueInt(t *testing.T) {
+	var i8 int8
+	var i64 int64
+	var i int
+	dec, _ := NewDecimal("12")
+	tests := []struct {
+		dest    interface{}
+		value   driver.Value
+		want    interface{}
+		wantErr bool
+	}{
+		{&i64, int64(-5), int64(-5), false},
+		{&i64, float64(42), int64(42), false},
+		{&i, dec, 12, false},
+		{&i8, int64(127), int8(127), false},
+		{&i8, int64(128), nil, true},
+		{&i8, int64(-129), nil, true},
+		{&i64, 1.5, nil, true},
+		{&i64, 1e19, nil, true},
+		{&i64, "1", nil, true},
+	}
+	for _, test := range tests {
+		err := setOutValue(test.dest, test.value)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("setOutValue(%T, %v) expected error", test.dest, test.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("setOutValue(%T, %v) unexpected error: %s", test.dest, test.value, err)
+			continue
+		}
+		if got := reflect.ValueOf(test.dest).Elem().Interface(); got != test.want {
+			t.Errorf("setOutValue(%T, %v) = %v, want %v", test.dest, test.value, got, test.want)
+		}
+	}
+}
+
+func TestSetOutValueUint(t *testing.T) {
+	var u8 uint8
+	var u64 uint64
+	var u uint
+	dec, _ := NewDecimal("18446744073709551615")
+	tests := []struct {
+		dest    interface{}
+		value   driver.Value
+		want    interface{}
+		wantErr bool
+	}{
+		{&u64, int64(5), uint64(5), false},
+		{&u64, float64(42), uint64(42), false},
+		{&u64, dec, uint64(math.MaxUint64), false},
+		{&u, uint64(7), uint(7), false},
+		{&u8, int64(255), uint8(255), false},
+		{&u8, int64(256), nil, true},
+		{&u64, int64(-1), nil, true},
+		{&u64, float64(-1), nil, true},
+		{&u64, 1.5, nil, true},
+		{&u64, 1e20, nil, true},
+		{&u64, "1", nil, true},
+	}
+	for _, test := range tests {
+		err := setOutValue(test.dest, test.value)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("setOutValue(%T, %v) expected error", test.dest, test.value)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("setOutValue(%T, %v) unexpected error: %s", test.dest, test.value, err)
+			continue
+		}
+		if got := reflect.ValueOf(test.dest).Elem().Interface(); got != test.want {
+			t.Errorf("setOutValue(%T, %v) = %v, want %v", test.dest, test.value, got, test.want)
+		}
+	}
+}
+
+func TestSetOutValueInterval(t *testing.T) {
+	want := -(36*time.Hour + 15*time.Minute + 1500*time.Millisecond)
+	value, err := converters.DecodeIntervalDS(converters.EncodeIntervalDS(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var duration time.Duration
+	if err = setOutValue(&duration, value); err != nil {
+		t.Fatal(err)
+	}
+	if duration != want {
+		t.Errorf("interval day to second output = %v, want %v", duration, want)
+	}
+	ym := converters.IntervalYM{Years: 2, Months: 5}
+	yValue, err := converters.DecodeIntervalYM(converters.EncodeIntervalYM(ym))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var yOut converters.IntervalYM
+	if err = setOutValue(&yOut, yValue); err != nil {
+		t.Fatal(err)
+	}
+	if yOut != ym {
+		t.Errorf("interval year to month output = %v, want %v", yOut, ym)
+	}
+	var i64 int64
+	if err = setOutValue(&i64, want); err == nil {
+		t.Errorf("expected error when storing interval into %T", &i64)
+	}
+}
+
+func TestUint64OutputRoundTrip(t *testing.T) {
+	var u64 uint64
+	var u uint
+	var slice []uint64
+	var i64 int64
+	for _, dest := range []interface{}{&u64, &u, &slice} {
+		if !exactNumberOutput(dest) {
+			t.Errorf("expected exact number output for %T", dest)
+		}
+	}
+	if exactNumberOutput(&i64) {
+		t.Errorf("unexpected exact number output for %T", &i64)
+	}
+	stmt := NewStmt("BEGIN :1 := :2; END;", newTestConnection())
+	par, err := stmt.newParam("", uint64(math.MaxUint64), 0, Input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := newDecimalFromBytes(par.BValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = setOutValue(&u64, value); err != nil {
+		t.Fatal(err)
+	}
+	if u64 != math.MaxUint64 {
+		t.Errorf("uint64 output = %d, want %d", u64, uint64(math.MaxUint64))
+	}
+}
+
+func TestLoadVectorColumn(t *testing.T) {
+	// column describe image built field by field for field version 24
+	image := func(flags byte) []byte {
+		return []byte{
+			byte(VECTOR), 0, 0, 0, // type, flag, precision and scale
+			0, 0, 0, 0, // max length, array elements, cont flag and toid
+			0, 0, 0, 0, // version, charset id and form, max char length
+			0, 1, 0, // collation, nullable and flag
+			1, 3, 3, 'E', 'M', 'B', 0, 0, // name, schema and type name
+			0, 0, 0, 0, // field version 3 and 6 fields and domain
+			1, 1, 0, 1, 1, 0, // annotations count and flags
+			1, 1, 1, 'k', 1, 1, 1, 'v', 0, 0, // annotation and flags
+			1, 3, byte(converters.VectorFloat32), flags, // dimensions, format and flags
+			0xEE,
+		}
+	}
+	tests := []struct {
+		flags      byte
+		dimensions int
+	}{
+		{0, 3},
+		{vectorFlexibleDimensions, 0},
+	}
+	for _, test := range tests {
+		conn := newTestConnection()
+		conn.session.StrConv = conn.strConv
+		conn.session.TTCVersion = ttcFieldVersionVector
+		conn.session.SetBuffer(image(test.flags))
+		par := new(ParameterInfo)
+		if err := par.load(conn); err != nil {
+			t.Fatal(err)
+		}
+		if par.Name != "EMB" || par.VectorDimensions != test.dimensions || par.VectorFormat != converters.VectorFloat32 {
+			t.Errorf("flags %d: column %s loaded with %d dimensions and %v format", test.flags,
+				par.Name, par.VectorDimensions, par.VectorFormat)
+		}
+		if next, err := conn.session.GetByte(); err != nil || next != 0xEE {
+			t.Errorf("flags %d: describe image not fully read, next byte %x", test.flags, next)
+		}
+		dataSet := &DataSet{Cols: []ParameterInfo{*par}}
+		if length, ok := dataSet.ColumnTypeLength(0); !ok || length != int64(test.dimensions) {
+			t.Errorf("flags %d: column type length = %d, %v", test.flags, length, ok)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+package go_ora
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPoolClosed  = errors.New("connection pool is closed")
+	ErrPoolTimeout = errors.New("timeout waiting for connection from the pool")
+)
+
+// Pool keep opened connections for reuse. the pool is controlled by the
+// pool options of ConnectionString:
+//
+//	MIN POOL SIZE:           connections opened when the pool is created and kept open
+//	MAX POOL SIZE:           maximum number of connections (idle + in use)
+//	INC POOL SIZE:           connections opened when the pool need to grow
+//	DECR POOL SIZE:          maximum idle connections closed in one regulator cycle
+//	POOL REGULATOR:          seconds between regulator cycles
+//	CONNECTION LIFETIME:     seconds after which a connection is closed instead of reused
+//	CONNECTION POOL TIMEOUT: seconds to wait for a free connection
+//	VALIDATE CONNECTION:     ping the connection before it is returned from Get
+//
+// a connection taken by Get return to the pool when it is closed
+type Pool struct {
+	conStr  *ConnectionString
+	mu      sync.Mutex
+	idle    []idleConn
+	open    int // idle + in use + being opened
+	waiters []chan *Connection
+	closed  bool
+	stop    chan struct{}
+}
+
+type idleConn struct {
+	conn  *Connection
+	since time.Time
+}
+
+// NewPool create connection pool and open MinPoolSize connections
+func NewPool(conStr *ConnectionString) (*Pool, error) {
+	if len(conStr.Host) == 0 {
+		return nil, errors.New("empty host name")
+	}
+	// defaults and validation apply to a copy so the caller value is kept
+	temp := *conStr
+	if temp.Port == 0 {
+		temp.Port = 1521
+	}
+	temp.Pooling = true
+	err := temp.validate()
+	if err != nil {
+		return nil, err
+	}
+	return newPool(context.Background(), &temp)
+}
+
+func newPool(ctx context.Context, conStr *ConnectionString) (*Pool, error) {
+	if conStr.MaxPoolSize <= 0 {
+		return nil, errors.New("MAX POOL SIZE should be greater than 0")
+	}
+	if conStr.MinPoolSize > conStr.MaxPoolSize {
+		return nil, errors.New("MIN POOL SIZE should not exceed MAX POOL SIZE")
+	}
+	pool := &Pool{
+		conStr: conStr,
+		stop:   make(chan struct{}),
+	}
+	pool.open = conStr.MinPoolSize
+	for x := 0; x < conStr.MinPoolSize; x++ {
+		conn, err := pool.newConn(ctx)
+		if err != nil {
+			pool.open -= conStr.MinPoolSize - x
+			_ = pool.Close()
+			return nil, err
+		}
+		pool.idle = append(pool.idle, idleConn{conn: conn, since: time.Now()})
+	}
+	if conStr.PoolRegulator > 0 {
+		go pool.regulate(time.Duration(conStr.PoolRegulator) * time.Second)
+	}
+	return pool, nil
+}
+
+func (pool *Pool) newConn(ctx context.Context) (*Connection, error) {
+	conn, err := openConnection(ctx, pool.conStr)
+	if err != nil {
+		return nil, err
+	}
+	conn.pool = pool
+	return conn, nil
+}
+
+// Get return idle connection from the pool or open new one if the pool
+// did not reach MaxPoolSize. otherwise it wait for a connection to be
+// returned until ConnectionPoolTimeout or ctx is done
+func (pool *Pool) Get(ctx context.Context) (*Connection, error) {
+	for {
+		pool.mu.Lock()
+		if pool.closed {
+			pool.mu.Unlock()
+			return nil, ErrPoolClosed
+		}
+		if n := len(pool.idle); n > 0 {
+			item := pool.idle[n-1]
+			pool.idle = pool.idle[:n-1]
+			pool.mu.Unlock()
+			conn, err := pool.checkout(ctx, item.conn)
+			if err != nil {
+				return nil, err
+			}
+			if conn != nil {
+				return conn, nil
+			}
+			continue
+		}
+		if pool.open < pool.conStr.MaxPoolSize {
+			grow := pool.conStr.IncrPoolSize
+			if grow < 1 {
+				grow = 1
+			}
+			if grow > pool.conStr.MaxPoolSize-pool.open {
+				grow = pool.conStr.MaxPoolSize - pool.open
+			}
+			pool.open += grow
+			pool.mu.Unlock()
+			conn, err := pool.newConn(ctx)
+			if err != nil {
+				pool.release(grow)
+				return nil, err
+			}
+			if grow > 1 {
+				go pool.fill(grow - 1)
+			}
+			return conn, nil
+		}
+		wait := make(chan *Connection, 1)
+		pool.waiters = append(pool.waiters, wait)
+		pool.mu.Unlock()
+		conn, err := pool.wait(ctx, wait)
+		if err != nil {
+			return nil, err
+		}
+		if conn == nil {
+			// a slot is free or the pool is closed
+			continue
+		}
+		conn, err = pool.checkout(ctx, conn)
+		if err != nil {
+			return nil, err
+		}
+		if conn != nil {
+			return conn, nil
+		}
+	}
+}
+
+func (pool *Pool) wait(ctx context.Context, wait chan *Connection) (*Connection, error) {
+	var timeout <-chan time.Time
+	if pool.conStr.ConnectionPoolTimeout > 0 {
+		timer := time.NewTimer(time.Duration(pool.conStr.ConnectionPoolTimeout) * time.Second)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var err error
+	select {
+	case conn := <-wait:
+		return conn, nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = ErrPoolTimeout
+	}
+	pool.mu.Lock()
+	for x, temp := range pool.waiters {
+		if temp == wait {
+			pool.waiters = append(pool.waiters[:x], pool.waiters[x+1:]...)
+			pool.mu.Unlock()
+			return nil, err
+		}
+	}
+	pool.mu.Unlock()
+	// a connection was handed to us at the same time so give it back
+	if conn := <-wait; conn != nil {
+		_ = pool.put(conn)
+	} else {
+		pool.notify()
+	}
+	return nil, err
+}
+
+// checkout return nil connection when the connection is expired or failed
+// validation so the caller try another one
+func (pool *Pool) checkout(ctx context.Context, conn *Connection) (*Connection, error) {
+	if pool.expired(conn) {
+		pool.discard(conn)
+		return nil, nil
+	}
+	if pool.conStr.ValidateConnection {
+		err := conn.Ping(ctx)
+		if err != nil {
+			pool.discard(conn)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, nil
+		}
+	}
+	return conn, nil
+}
+
+func (pool *Pool) expired(conn *Connection) bool {
+	return pool.conStr.ConnectionLifeTime > 0 &&
+		time.Since(conn.openTime) > time.Duration(pool.conStr.ConnectionLifeTime)*time.Second
+}
+
+// put is called when pooled connection is closed
+func (pool *Pool) put(conn *Connection) error {
+	if !conn.IsValid() {
+		// broken sessions are closed instead of reused
+		pool.discard(conn)
+		return nil
+	}
+	if !conn.autoCommit {
+		// don't leak uncommitted work to the next user
+		err := (&Transaction{conn: conn}).Rollback()
+		if err != nil {
+			pool.discard(conn)
+			return nil
+		}
+	}
+	if pool.expired(conn) {
+		pool.discard(conn)
+		return nil
+	}
+	if pool.conStr.StmtCachePurge {
+		if err := conn.purgeStmtCache(); err != nil {
+			pool.discard(conn)
+			return nil
+		}
+	}
+	pool.mu.Lock()
+	if pool.closed {
+		pool.mu.Unlock()
+		pool.discard(conn)
+		return nil
+	}
+	for _, item := range pool.idle {
+		if item.conn == conn {
+			// connection closed twice
+			pool.mu.Unlock()
+			return nil
+		}
+	}
+	if len(pool.waiters) > 0 {
+		wait := pool.waiters[0]
+		pool.waiters = pool.waiters[1:]
+		pool.mu.Unlock()
+		wait <- conn
+		return nil
+	}
+	pool.idle = append(pool.idle, idleConn{conn: conn, since: time.Now()})
+	pool.mu.Unlock()
+	return nil
+}
+
+// discard close the connection and free its slot
+func (pool *Pool) discard(conn *Connection) {
+	conn.pool = nil
+	_ = conn.Close()
+	pool.release(1)
+}
+
+// release free slots reserved for connections that are closed or failed to
+// open and wake up one waiter to use it
+func (pool *Pool) release(count int) {
+	pool.mu.Lock()
+	pool.open -= count
+	pool.mu.Unlock()
+	pool.notify()
+}
+
+func (pool *Pool) notify() {
+	pool.mu.Lock()
+	if len(pool.waiters) > 0 {
+		wait := pool.waiters[0]
+		pool.waiters = pool.waiters[1:]
+		pool.mu.Unlock()
+		wait <- nil
+		return
+	}
+	pool.mu.Unlock()
+}
+
+// fill open count connections into reserved slots
+func (pool *Pool) fill(count int) {
+	for x := 0; x < count; x++ {
+		conn, err := pool.newConn(context.Background())
+		if err != nil {
+			pool.release(count - x)
+			return
+		}
+		_ = pool.put(conn)
+	}
+}
+
+// regulate close expired connections, shrink the pool by DecrPoolSize
+// connections that stay idle for a whole cycle and keep MinPoolSize open
+func (pool *Pool) regulate(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pool.stop:
+			return
+		case <-ticker.C:
+		}
+		var toClose []*Connection
+		pool.mu.Lock()
+		kept := pool.idle[:0]
+		decr := pool.conStr.DecrPoolSize
+		for _, item := range pool.idle {
+			if pool.expired(item.conn) {
+				toClose = append(toClose, item.conn)
+			} else if decr > 0 && time.Since(item.since) >= interval &&
+				pool.open-len(toClose) > pool.conStr.MinPoolSize {
+				toClose = append(toClose, item.conn)
+				decr--
+			} else {
+				kept = append(kept, item)
+			}
+		}
+		pool.idle = kept
+		pool.mu.Unlock()
+		for _, conn := range toClose {
+			pool.discard(conn)
+		}
+		pool.mu.Lock()
+		missing := 0
+		if !pool.closed && pool.open < pool.conStr.MinPoolSize {
+			missing = pool.conStr.MinPoolSize - pool.open
+			pool.open += missing
+		}
+		pool.mu.Unlock()
+		if missing > 0 {
+			pool.fill(missing)
+		}
+	}
+}
+
+// Close close idle connections. connections in use are closed when they
+// are returned
+func (pool *Pool) Close() error {
+	pool.mu.Lock()
+	if pool.closed {
+		pool.mu.Unlock()
+		return nil
+	}
+	pool.closed = true
+	close(pool.stop)
+	idle := pool.idle
+	pool.idle = nil
+	waiters := pool.waiters
+	pool.waiters = nil
+	pool.mu.Unlock()
+	for _, wait := range waiters {
+		wait <- nil
+	}
+	for _, item := range idle {
+		pool.discard(item.conn)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package go_ora
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sijms/go-ora/v2/converters"
+	"github.com/sijms/go-ora/v2/network"
+	"github.com/sijms/go-ora/v2/trace"
+)
+
+func newTestConnection() *Connection {
+	option := &network.ConnectionOption{}
+	option.Tracer = trace.NilTracer()
+	return &Connection{
+		State:      Opened,
+		autoCommit: true,
+		conStr:     &ConnectionString{},
+		connOption: option,
+		session:    network.NewSession(option),
+		tcpNego:    &TCPNego{ServerCharset: 873, ServernCharset: 2000},
+		strConv:    converters.NewStringConverter(873),
+		dBVersion:  &DBVersion{MajorVersion: 19},
+		openTime:   time.Now(),
+	}
+}
+
+func newTestPool(maxSize, open int) *Pool {
+	return &Pool{
+		conStr: &ConnectionString{MaxPoolSize: maxSize},
+		open:   open,
+		stop:   make(chan struct{}),
+	}
+}
+
+func TestPoolPut(t *testing.T) {
+	pool := newTestPool(2, 1)
+	conn := newTestConnection()
+	conn.pool = pool
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(pool.idle) != 1 || pool.idle[0].conn != conn || pool.open != 1 {
+		t.Errorf("healthy connection should be idle: idle=%d open=%d", len(pool.idle), pool.open)
+	}
+	// closed twice
+	_ = conn.Close()
+	if len(pool.idle) != 1 {
+		t.Errorf("connection closed twice is idle %d times", len(pool.idle))
+	}
+	got, err := pool.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if got != conn || len(pool.idle) != 0 {
+		t.Errorf("Get should return the idle connection")
+	}
+}
+
+func TestPoolDiscardInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(conn *Connection, pool *Pool)
+	}{
+		{"closed state", func(conn *Connection, pool *Pool) { conn.State = Closed }},
+		{"no session", func(conn *Connection, pool *Pool) { conn.session = nil }},
+		{"expired", func(conn *Connection, pool *Pool) {
+			pool.conStr.ConnectionLifeTime = 1
+			conn.openTime = time.Now().Add(-time.Minute)
+		}},
+		{"pool closed", func(conn *Connection, pool *Pool) { _ = pool.Close() }},
+	}
+	for _, test := range tests {
+		pool := newTestPool(2, 1)
+		conn := newTestConnection()
+		conn.pool = pool
+		test.modify(conn, pool)
+		_ = conn.Close()
+		if len(pool.idle) != 0 || pool.open != 0 || conn.pool != nil {
+			t.Errorf("%s: connection should be discarded: idle=%d open=%d", test.name, len(pool.idle), pool.open)
+		}
+	}
+}
+
+func TestPoolWaiter(t *testing.T) {
+	pool := newTestPool(1, 1)
+	conn := newTestConnection()
+	conn.pool = pool
+	result := make(chan *Connection)
+	go func() {
+		got, _ := pool.Get(context.Background())
+		result <- got
+	}()
+	for {
+		pool.mu.Lock()
+		waiting := len(pool.waiters)
+		pool.mu.Unlock()
+		if waiting > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	_ = conn.Close()
+	if got := <-result; got != conn {
+		t.Errorf("waiter should receive the returned connection")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Get(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Get on full pool = %v, want %v", err, context.DeadlineExceeded)
+	}
+	pool.mu.Lock()
+	waiting := len(pool.waiters)
+	pool.mu.Unlock()
+	if waiting != 0 {
+		t.Errorf("timed out waiter is not removed")
+	}
+	_ = pool.Close()
+	if _, err := pool.Get(context.Background()); err != ErrPoolClosed {
+		t.Errorf("Get on closed pool = %v, want %v", err, ErrPoolClosed)
+	}
+}
+
+func TestNewPoolKeepConnectionString(t *testing.T) {
+	conStr := &ConnectionString{Host: "localhost", UserID: "user", Password: "pass",
+		ServiceName: "orcl", MaxPoolSize: 2}
+	pool, err := NewPool(conStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	if conStr.Port != 0 || conStr.Pooling {
+		t.Errorf("caller connection string modified: port %d, pooling %v", conStr.Port, conStr.Pooling)
+	}
+	if pool.conStr.Port != 1521 || !pool.conStr.Pooling {
+		t.Errorf("expected pool defaults port 1521 and pooling, got %d and %v", pool.conStr.Port, pool.conStr.Pooling)
+	}
+}
@@ -1,10 +1,24 @@
 package go_ora
 
+import "database/sql/driver"
+
+// Compile time Sentinels for implemented Interfaces.
+var _ = driver.Rows((*RefCursor)(nil))
+
+// RefCursor is REF CURSOR returned from output parameter or select list.
+// *RefCursor is driver.Rows so it can be scanned into *sql.Rows from a
+// select list or received with sql.Out{Dest: &rows} where rows is
+// driver.Rows. fetching rows need the connection so use sql.Conn to keep
+// the connection while the rows are iterated
 type RefCursor struct {
 	defaultStmt
 	len        uint8
 	MaxRowSize int
 	parent     *defaultStmt
+	dataSet    *DataSet
+	// row is index of the row in the fetched rows of the parent for cursor
+	// in select list
+	row int
 	//ID         int
 	//scnForSnapshot []int
 	//connection *Connection
@@ -12,7 +26,28 @@ type RefCursor struct {
 	//hasMoreRows bool
 }
 
+// load read cursor of output parameter
 func (cursor *RefCursor) load() error {
+	err := cursor.decode()
+	if err != nil {
+		return err
+	}
+	_, err = cursor.connection.session.GetInt(2, true, true)
+	return err
+}
+
+// decode read cursor description and id
+func (cursor *RefCursor) decode() error {
+	var err error
+	cursor.len, err = cursor.connection.session.GetByte()
+	if err != nil {
+		return err
+	}
+	return cursor.describe()
+}
+
+// describe read column definitions and cursor id
+func (cursor *RefCursor) describe() error {
 	// initialize ref cursor object
 	cursor.text = ""
 	cursor._hasLONG = false
@@ -24,10 +59,6 @@ func (cursor *RefCursor) load() error {
 	cursor.stmtType = SELECT
 	session := cursor.connection.session
 	var err error
-	cursor.len, err = session.GetByte()
-	if err != nil {
-		return err
-	}
 	cursor.MaxRowSize, err = session.GetInt(4, true, true)
 	if err != nil {
 		return err
@@ -83,10 +114,6 @@ func (cursor *RefCursor) load() error {
 	if err != nil {
 		return err
 	}
-	_, err = session.GetInt(2, true, true)
-	if err != nil {
-		return err
-	}
 	return nil
 }
 func (cursor *RefCursor) getExeOptions() int {
@@ -106,6 +133,11 @@ func (cursor *RefCursor) Query() (*DataSet, error) {
 		return nil, err
 	}
 	dataSet := new(DataSet)
+	if len(cursor.columns) > 0 {
+		dataSet.ColumnCount = len(cursor.columns)
+		dataSet.Cols = make([]ParameterInfo, len(cursor.columns))
+		copy(dataSet.Cols, cursor.columns)
+	}
 	err = cursor.read(dataSet)
 	if err != nil {
 		return nil, err
@@ -120,8 +152,40 @@ func (cursor *RefCursor) write() error {
 	return cursor.connection.session.Write()
 }
 
+// Close close the cursor and its nested cursors on the server
 func (cursor *RefCursor) Close() error {
-	return nil
+	if cursor.dataSet != nil {
+		_ = cursor.dataSet.closeNested()
+	}
+	if cursor.cursorID == 0 || cursor.connection.session == nil {
+		return nil
+	}
+	err := cursor.defaultStmt.Close()
+	cursor.cursorID = 0
+	return err
+}
+
+func (cursor *RefCursor) Columns() []string {
+	if len(cursor.columns) == 0 {
+		return nil
+	}
+	ret := make([]string, len(cursor.columns))
+	for x := 0; x < len(cursor.columns); x++ {
+		ret[x] = cursor.columns[x].Name
+	}
+	return ret
+}
+
+// Next fetch rows of the cursor. the cursor is executed on first call
+func (cursor *RefCursor) Next(dest []driver.Value) error {
+	if cursor.dataSet == nil {
+		dataSet, err := cursor.Query()
+		if err != nil {
+			return err
+		}
+		cursor.dataSet = dataSet
+	}
+	return cursor.dataSet.Next(dest)
 }
 
 //func (cursor *RefCursor) Exec(args []driver.Value) (driver.Result, error) {
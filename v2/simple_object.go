@@ -17,7 +17,7 @@ type simpleObject struct {
 func (obj *simpleObject) write() *simpleObject {
 	//obj.session.ResetBuffer()
 	session := obj.connection.session
-	session.PutBytes(3, obj.operationID, 0)
+	session.PutFunctionCode(3, obj.operationID)
 	if obj.data != nil {
 		session.PutBytes(obj.data...)
 	}
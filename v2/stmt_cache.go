@@ -0,0 +1,81 @@
+package go_ora
+
+import "container/list"
+
+// stmtCache keep server cursors of closed statements keyed by sql text so
+// the next statement with the same text skip parse and define. the least
+// recently used cursor is closed when the cache is full
+type stmtCache struct {
+	size  int
+	items map[string]*list.Element
+	lru   *list.List
+}
+
+type cachedStmt struct {
+	connection *Connection
+	text       string
+	cursorID   int
+	stmtType   StmtType
+	columns    []ParameterInfo
+	hasLONG    bool
+	hasBLOB    bool
+	queryID    uint64
+}
+
+func newStmtCache(size int) *stmtCache {
+	if size <= 0 {
+		return nil
+	}
+	return &stmtCache{
+		size:  size,
+		items: make(map[string]*list.Element, size),
+		lru:   list.New(),
+	}
+}
+
+// get remove the cursor from the cache and return it. the cursor is owned
+// by the caller until it is put back
+func (cache *stmtCache) get(text string) *cachedStmt {
+	if elem, ok := cache.items[text]; ok {
+		delete(cache.items, text)
+		cache.lru.Remove(elem)
+		return elem.Value.(*cachedStmt)
+	}
+	return nil
+}
+
+// put add the cursor to the cache and return cursors that should be closed
+// on the server
+func (cache *stmtCache) put(item *cachedStmt) []*cachedStmt {
+	var evicted []*cachedStmt
+	if elem, ok := cache.items[item.text]; ok {
+		// another statement with the same text hold its own cursor
+		evicted = append(evicted, elem.Value.(*cachedStmt))
+		cache.lru.Remove(elem)
+	}
+	cache.items[item.text] = cache.lru.PushFront(item)
+	for cache.lru.Len() > cache.size {
+		elem := cache.lru.Back()
+		temp := elem.Value.(*cachedStmt)
+		cache.lru.Remove(elem)
+		delete(cache.items, temp.text)
+		evicted = append(evicted, temp)
+	}
+	return evicted
+}
+
+// purge empty the cache and return all cursors
+func (cache *stmtCache) purge() []*cachedStmt {
+	evicted := make([]*cachedStmt, 0, cache.lru.Len())
+	for elem := cache.lru.Front(); elem != nil; elem = elem.Next() {
+		evicted = append(evicted, elem.Value.(*cachedStmt))
+	}
+	cache.items = make(map[string]*list.Element, cache.size)
+	cache.lru.Init()
+	return evicted
+}
+
+func (item *cachedStmt) close() error {
+	stmt := defaultStmt{connection: item.connection, cursorID: item.cursorID}
+	return stmt.Close()
+}
@@ -0,0 +1,36 @@
+package go_ora
+
+import "testing"
+
+func TestStmtCache(t *testing.T) {
+	if newStmtCache(0) != nil {
+		t.Errorf("cache with size 0 should be disabled")
+	}
+	cache := newStmtCache(2)
+	if evicted := cache.put(&cachedStmt{text: "A", cursorID: 1}); len(evicted) != 0 {
+		t.Errorf("put evicted %d cursors from empty cache", len(evicted))
+	}
+	cache.put(&cachedStmt{text: "B", cursorID: 2})
+	// A become the most recently used
+	item := cache.get("A")
+	if item == nil || item.cursorID != 1 {
+		t.Fatalf("get(A) = %v", item)
+	}
+	if cache.get("A") != nil {
+		t.Errorf("cursor is owned by the caller after get")
+	}
+	cache.put(item)
+	evicted := cache.put(&cachedStmt{text: "C", cursorID: 3})
+	if len(evicted) != 1 || evicted[0].cursorID != 2 {
+		t.Errorf("put should evict least recently used cursor: %v", evicted)
+	}
+	// same text replace the cached cursor
+	evicted = cache.put(&cachedStmt{text: "C", cursorID: 4})
+	if len(evicted) != 1 || evicted[0].cursorID != 3 {
+		t.Errorf("put should return replaced cursor: %v", evicted)
+	}
+	evicted = cache.purge()
+	if len(evicted) != 2 || cache.lru.Len() != 0 || len(cache.items) != 0 {
+		t.Errorf("purge returned %d cursors and left %d", len(evicted), cache.lru.Len())
+	}
+}
@@ -0,0 +1,61 @@
+package go_ora
+
+import (
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/sijms/go-ora/v2/converters"
+)
+
+// Vector is value of VECTOR column. Values is []float32, []float64 or
+// []int8 and its element type select the storage format. slices are
+// used for array binding of DML so VECTOR parameters of INSERT, UPDATE and
+// DELETE should be wrapped in Vector. other statements bind these slices
+// as VECTOR
+//
+//	_, err = db.Exec("INSERT INTO T1(V) VALUES(:1)", go_ora.Vector{Values: []float32{1, 2, 3}})
+//
+// VECTOR columns are returned as []float32, []float64, []int8 or []uint8
+// for BINARY format and can be scanned directly into the matching slice
+type Vector struct {
+	Values interface{}
+}
+
+func (vec Vector) encode() ([]byte, error) {
+	if vec.Values == nil {
+		return nil, nil
+	}
+	return converters.EncodeVector(vec.Values)
+}
+
+// Scan implement sql.Scanner. NULL is scanned as nil Values
+func (vec *Vector) Scan(value interface{}) error {
+	switch val := value.(type) {
+	case Vector:
+		*vec = val
+	case []float32, []float64, []int8, []uint8, nil:
+		vec.Values = val
+	default:
+		return fmt.Errorf("cannot scan value of type %T into Vector", value)
+	}
+	return nil
+}
+
+// vectorArgs wrap []float32, []float64 and []int8 arguments in Vector. it
+// is used for statements that do not support array binding
+func vectorArgs(args []driver.NamedValue) []driver.NamedValue {
+	var ret []driver.NamedValue
+	for x, arg := range args {
+		switch arg.Value.(type) {
+		case []float32, []float64, []int8:
+			if ret == nil {
+				ret = append(make([]driver.NamedValue, 0, len(args)), args...)
+			}
+			ret[x].Value = Vector{Values: arg.Value}
+		}
+	}
+	if ret == nil {
+		return args
+	}
+	return ret
+}
@@ -0,0 +1,60 @@
+package go_ora
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xmlTypeOID is type oid of SYS.XMLTYPE
+var xmlTypeOID = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 1, 0}
+
+// XML is document of XMLTYPE. XMLTYPE columns are returned as string that
+// can be scanned into string or XML. XML is bound as XMLTYPE
+//
+//	_, err = db.Exec("INSERT INTO MESSAGES(ID, PAYLOAD) VALUES(:1, :2)", id, go_ora.XML{Text: envelope})
+//
+// *XML implement io.Reader so scanned document can be passed to
+// xml.NewDecoder
+type XML struct {
+	Text   string
+	reader *strings.Reader
+}
+
+var _ io.Reader = (*XML)(nil)
+
+func (doc XML) String() string {
+	return doc.Text
+}
+
+// Reader return new io.Reader of the document text
+func (doc XML) Reader() io.Reader {
+	return strings.NewReader(doc.Text)
+}
+
+// Read implement io.Reader. it read the document text from the start after
+// each Scan
+func (doc *XML) Read(p []byte) (int, error) {
+	if doc.reader == nil {
+		doc.reader = strings.NewReader(doc.Text)
+	}
+	return doc.reader.Read(p)
+}
+
+// Scan implement sql.Scanner. NULL is scanned as empty document
+func (doc *XML) Scan(value interface{}) error {
+	switch val := value.(type) {
+	case XML:
+		doc.Text = val.Text
+	case string:
+		doc.Text = val
+	case []byte:
+		doc.Text = string(val)
+	case nil:
+		doc.Text = ""
+	default:
+		return fmt.Errorf("cannot scan value of type %T into XML", value)
+	}
+	doc.reader = nil
+	return nil
+}
@@ -0,0 +1,49 @@
+package go_ora
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+)
+
+func TestXMLParam(t *testing.T) {
+	stmt := NewStmt("INSERT INTO T1 VALUES(:1)", newTestConnection())
+	par, err := stmt.newParam("", XML{Text: "<a/>"}, 0, Input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	wantOID := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 1, 0}
+	if par.DataType != XMLType || !reflect.DeepEqual(par.ToID, wantOID) {
+		t.Errorf("XML bound as %v with type oid %x", par.DataType, par.ToID)
+	}
+	wantImage := []byte{0x85, 1, 0xFE, 0, 0, 0, 16, 1, 0, 0, 0, 4, '<', 'a', '/', '>'}
+	if !reflect.DeepEqual(par.BValue, wantImage) {
+		t.Errorf("XML bind image = %v, want %v", par.BValue, wantImage)
+	}
+}
+
+func TestXMLReader(t *testing.T) {
+	var doc XML
+	if err := doc.Scan("<a><b>1</b></a>"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	var value struct {
+		B int `xml:"b"`
+	}
+	if err := xml.NewDecoder(&doc).Decode(&value); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if value.B != 1 {
+		t.Errorf("decoded value = %d, want 1", value.B)
+	}
+	// Scan restart the reader
+	if err := doc.Scan([]byte("<a><b>2</b></a>")); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := xml.NewDecoder(&doc).Decode(&value); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if value.B != 2 {
+		t.Errorf("decoded value = %d, want 2", value.B)
+	}
+}